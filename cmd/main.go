@@ -2,108 +2,138 @@ package main
 
 import (
 	"fmt"
-	"math/rand"
 	"time"
 
 	dataset "github.com/SobhanYasami/nn-go/internal/data"
+	"github.com/SobhanYasami/nn-go/internal/mathx"
 	"github.com/SobhanYasami/nn-go/internal/nn"
+	"github.com/SobhanYasami/nn-go/internal/rng"
+	"github.com/SobhanYasami/nn-go/internal/scheduler"
 	"github.com/SobhanYasami/nn-go/internal/utils"
 	"github.com/SobhanYasami/nn-go/pkg/logger"
 )
 
+// seed makes every run identical; each consumer draws from its own named stream.
+const seed = 42
+
 func main() {
 	log := logger.New("main", logger.DEBUG)
-	log.Info("Starting hill climbing neural network demo...")
+	log.Info("Starting spiral classification training demo...")
 
 	start := time.Now()
+	root := rng.New(seed)
 
 	// --- Step 1: Create dataset ---
-	X, y := dataset.CreateData(300, 3)
-	fmt.Println("Generated", len(X), "points")
+	points, y := dataset.CreateDataWithRNG(300, 3, root.Stream("train"))
+	fmt.Println("Generated", len(points), "points")
 
-	if err := utils.PlotData(X, y, 3, "spiral.png"); err != nil {
+	if err := utils.PlotData(points, y, 3, "spiral.png"); err != nil {
 		panic(err)
 	}
+	X, err := mathx.FromMatrix(points)
+	if err != nil {
+		log.Error("Error building dataset: %v", err)
+		return
+	}
 	fmt.Println("✅ Dataset ready.")
 
-	// --- Step 2: Initialize activation and loss functions ---
-	af := nn.ActivationFn{}
-	lf := nn.LossFn{}
+	// --- Step 2: Initialize loss function ---
+	lossFn, err := nn.NewLoss("categorical_crossentropy", nil)
+	if err != nil {
+		log.Error("Error creating loss: %v", err)
+		return
+	}
 
 	// --- Step 3: Define network architecture ---
 	layerSizes := []int{2, 8, 8, 6, 6, 4, 3}
-	layers := make([]*nn.DenseLayer, len(layerSizes)-1)
+	model := nn.NewSequential()
 	for i := 0; i < len(layerSizes)-1; i++ {
-		layer, err := nn.NewDenseLayer(layerSizes[i], layerSizes[i+1])
+		// He init keeps ReLU activations from vanishing; Glorot suits the softmax output.
+		init := nn.HeNormal()
+		if i == len(layerSizes)-2 {
+			init = nn.GlorotUniform()
+		}
+		layer, err := nn.NewDenseLayer(layerSizes[i], layerSizes[i+1],
+			nn.WithWeightInit(init), nn.WithRand(root.Stream(fmt.Sprintf("dense%d", i)).Rand))
 		if err != nil {
 			log.Error("Error creating layer %d: %v", i, err)
 			return
 		}
-		layers[i] = layer
+		model.Add(layer)
+		if i < len(layerSizes)-2 {
+			model.Add(nn.NewActivationLayer(nn.ReLU))
+		}
 	}
+	model.Add(nn.NewActivationLayer(nn.Softmax))
 
 	// --- Step 4: Evaluate initial loss ---
-	initialLoss := computeLoss(X, y, layers, &af, &lf)
-	bestLoss := initialLoss
-	fmt.Printf("Initial loss: %.6f\n", bestLoss)
-
-	// --- Step 5: Hill climbing optimization ---
-	rand.Seed(time.Now().UnixNano())
-	// bestWeights := cloneNetwork(layers)
-
-	numIterations := 1000
-	fmt.Printf("🚀 Running hill climbing for %d iterations...\n", numIterations)
-
-	learningRate := 0.01
-	epochs := 10000
-
-	for epoch := 0; epoch < epochs; epoch++ {
-		// Forward pass
-		input := X
-		for i, layer := range layers {
-			output, _ := layer.Forward(input)
-			if i < len(layers)-1 {
-				_ = af.ReLUInPlace(output)
-			}
-			input = output
-		}
-		_ = af.SoftmaxInPlace(input)
-
-		// Compute loss
-		loss, _ := lf.CategoricalCrossEntropy(input, y)
-
-		// Backward pass
-		dInputs := lf.SoftmaxCrossEntropyBackward(input, y)
-		for i := len(layers) - 1; i >= 0; i-- {
-			if i < len(layers)-1 {
-				prev := layers[i].Output
-				dInputs = af.ReLUBackward(dInputs, prev)
-			}
-			dInputs = layers[i].Backward(dInputs, learningRate)
-		}
+	initialLoss := computeLoss(X, y, model, lossFn)
+	fmt.Printf("Initial loss: %.6f\n", initialLoss)
+
+	// --- Step 5: Gradient-based optimization ---
+	optimizer, err := nn.NewAdam(0.01, 0.9, 0.999, 1e-8)
+	if err != nil {
+		log.Error("Error creating optimizer: %v", err)
+		return
+	}
+	epochs := 500
 
-		if epoch%100 == 0 {
-			fmt.Printf("Epoch %4d | Loss: %.6f\n", epoch, loss)
-		}
+	cosine, err := scheduler.NewCosineWarmRestarts(0.01, 1e-4, 50, 2)
+	if err != nil {
+		log.Error("Error creating scheduler: %v", err)
+		return
+	}
+	lrSchedule, err := scheduler.NewLinearWarmup(10, 0.1, cosine)
+	if err != nil {
+		log.Error("Error creating scheduler: %v", err)
+		return
+	}
+
+	trainer, err := nn.NewTrainer(model, lossFn, optimizer, nn.Dataset{X: X, Y: y})
+	if err != nil {
+		log.Error("Error creating trainer: %v", err)
+		return
+	}
+	valPoints, valY := dataset.CreateDataWithRNG(100, 3, root.Stream("validation"))
+	valX, err := mathx.FromMatrix(valPoints)
+	if err != nil {
+		log.Error("Error building validation set: %v", err)
+		return
+	}
+	trainer.Validation = &nn.Dataset{X: valX, Y: valY}
+	trainer.Scheduler = lrSchedule
+	trainer.BatchSize = 32
+	trainer.RNG = root.Stream("shuffle")
+	earlyStopping, err := nn.NewEarlyStopping("val_loss", 50, 1e-4, true)
+	if err != nil {
+		log.Error("Error creating early stopping: %v", err)
+		return
+	}
+	trainer.AddCallback(nn.NewLoggingCallback(logger.New("train", logger.INFO), 25), earlyStopping)
+
+	fmt.Printf("🚀 Training for up to %d epochs...\n", epochs)
+	if _, err := trainer.Fit(epochs); err != nil {
+		log.Error("Training failed: %v", err)
+		return
+	}
+	bestLoss, bestEpoch := earlyStopping.Best()
+	if stopped := earlyStopping.StoppedEpoch(); stopped >= 0 {
+		fmt.Printf("⏹️ Early stopping at epoch %d\n", stopped)
 	}
 
 	fmt.Printf("\n🏁 Optimization completed.\n")
-	fmt.Printf("🔹 Best loss: %.6f\n", bestLoss)
+	fmt.Printf("🔹 Best validation loss: %.6f (epoch %d, weights restored)\n", bestLoss, bestEpoch)
+	fmt.Printf("🔹 Final training loss: %.6f\n", computeLoss(X, y, model, lossFn))
 	fmt.Printf("⏱️ Total runtime: %v\n", time.Since(start))
 }
 
 // ----------------- Helper functions -----------------
 
-func computeLoss(X [][]float64, y []int, layers []*nn.DenseLayer, af *nn.ActivationFn, lf *nn.LossFn) float64 {
-	input := X
-	for i, layer := range layers {
-		output, _ := layer.Forward(input)
-		if i < len(layers)-1 {
-			_ = af.ReLUInPlace(output)
-		}
-		input = output
+func computeLoss(X *mathx.Tensor, y []int, model *nn.Sequential, lossFn nn.Loss) float64 {
+	output, err := model.Forward(X)
+	if err != nil {
+		return 0
 	}
-	_ = af.SoftmaxInPlace(input)
-	loss, _ := lf.CategoricalCrossEntropy(input, y)
+	loss, _ := lossFn.Forward(output, nn.Target{Labels: y})
 	return loss
 }
@@ -2,18 +2,23 @@ package dataset
 
 import (
 	"math"
-	"math/rand"
-	"time"
+
+	"github.com/SobhanYasami/nn-go/internal/rng"
 )
 
 // CreateData generates a 2D spiral dataset similar to Karpathy's CS231n example.
 // samples: number of points per class
 // classes: number of classes (spirals)
 // returns: X (features), y (labels)
+//
+// The noise is seeded from the clock; use CreateDataWithRNG for a reproducible dataset.
 func CreateData(samples, classes int) ([][]float64, []int) {
-	// Local random generator (no deprecated global seed)
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return CreateDataWithRNG(samples, classes, rng.NewFromTime())
+}
 
+// CreateDataWithRNG is CreateData drawing its noise from r, so the same
+// seed always produces the same points.
+func CreateDataWithRNG(samples, classes int, r *rng.RNG) ([][]float64, []int) {
 	total := samples * classes
 	X := make([][]float64, total)
 	y := make([]int, total)
@@ -22,13 +27,13 @@ func CreateData(samples, classes int) ([][]float64, []int) {
 		for i := 0; i < samples; i++ {
 			ix := i + classNum*samples
 
-			r := float64(i) / float64(samples-1) // radius
+			radius := float64(i) / float64(samples-1)
 			t := float64(classNum)*4.0 + float64(i)/float64(samples-1)*4.0
-			t += rng.NormFloat64() * 0.2 // add gaussian noise
+			t += r.NormFloat64() * 0.2 // add gaussian noise
 
 			X[ix] = []float64{
-				r * math.Sin(t*2.5),
-				r * math.Cos(t*2.5),
+				radius * math.Sin(t*2.5),
+				radius * math.Cos(t*2.5),
 			}
 			y[ix] = classNum
 		}
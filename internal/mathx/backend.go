@@ -0,0 +1,125 @@
+package mathx
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+)
+
+// Backend computes the dense linear algebra behind NumGo, Gemm and MatMul.
+// Vector arguments of the level-1 operations must have equal lengths.
+type Backend interface {
+	// Name identifies the backend, as accepted by NewBackend.
+	Name() string
+	// Gemm computes C = alpha·op(A)·op(B) + beta·C; see the package-level Gemm.
+	Gemm(transA, transB bool, alpha float64, a, b *Tensor, beta float64, c *Tensor) error
+	// Transpose returns a contiguous copy of the transpose of a 2-D tensor.
+	Transpose(t *Tensor) *Tensor
+	// Dot returns Σ x[i]·y[i].
+	Dot(x, y []float64) float64
+	// Axpy computes y += alpha·x in place.
+	Axpy(alpha float64, x, y []float64)
+	// Scal computes x *= alpha in place.
+	Scal(alpha float64, x []float64)
+	// Nrm2 returns the Euclidean norm of x.
+	Nrm2(x []float64) float64
+}
+
+// NewBackend returns the backend registered under name: "native" for the
+// built-in kernels or "gonum" for gonum's pure-Go BLAS.
+func NewBackend(name string) (Backend, error) {
+	switch name {
+	case "native":
+		return NativeBackend{}, nil
+	case "gonum":
+		return GonumBackend{}, nil
+	default:
+		return nil, fmt.Errorf("unknown backend %q", name)
+	}
+}
+
+// defaultBackend is used by Gemm, MatMul and every NumGo without a Backend.
+var defaultBackend atomic.Pointer[Backend]
+
+// DefaultBackend returns the package-wide backend, NativeBackend unless
+// SetDefaultBackend chose another.
+func DefaultBackend() Backend {
+	if b := defaultBackend.Load(); b != nil {
+		return *b
+	}
+	return NativeBackend{}
+}
+
+// SetDefaultBackend switches the package-wide backend; nil restores
+// NativeBackend. It is safe to call while other goroutines compute.
+func SetDefaultBackend(b Backend) {
+	if b == nil {
+		defaultBackend.Store(nil)
+		return
+	}
+	defaultBackend.Store(&b)
+}
+
+// backend returns the backend of ng, falling back to the package default.
+func (ng *NumGo) backend() Backend {
+	if ng.Backend != nil {
+		return ng.Backend
+	}
+	return DefaultBackend()
+}
+
+//? --------------------
+//? Native Backend
+//? --------------------
+
+// NativeBackend runs the dependency-free kernels of this package: the tiled,
+// parallel GEMM and plain loops for the vector operations.
+type NativeBackend struct{}
+
+// Name implements Backend.
+func (NativeBackend) Name() string { return "native" }
+
+// Gemm implements Backend.
+func (NativeBackend) Gemm(transA, transB bool, alpha float64, a, b *Tensor, beta float64, c *Tensor) error {
+	return gemmNative(transA, transB, alpha, a, b, beta, c)
+}
+
+// Transpose implements Backend.
+func (NativeBackend) Transpose(t *Tensor) *Tensor { return t.T().Clone() }
+
+// Dot implements Backend.
+func (NativeBackend) Dot(x, y []float64) float64 {
+	var sum float64
+	for i := range x {
+		sum += x[i] * y[i]
+	}
+	return sum
+}
+
+// Axpy implements Backend.
+func (NativeBackend) Axpy(alpha float64, x, y []float64) {
+	for i := range x {
+		y[i] += alpha * x[i]
+	}
+}
+
+// Scal implements Backend. As in BLAS, alpha 0 clears x even where it holds
+// NaN or ±Inf.
+func (NativeBackend) Scal(alpha float64, x []float64) {
+	if alpha == 0 {
+		clear(x)
+		return
+	}
+	for i := range x {
+		x[i] *= alpha
+	}
+}
+
+// Nrm2 implements Backend.
+func (NativeBackend) Nrm2(x []float64) float64 {
+	var sum float64
+	for _, v := range x {
+		sum += v * v
+	}
+	return math.Sqrt(sum)
+}
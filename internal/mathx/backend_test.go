@@ -0,0 +1,181 @@
+package mathx
+
+import (
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+// closeSlices reports whether a and b agree elementwise within a relative
+// tolerance; the backends may sum in different orders.
+func closeSlices(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-12*max(1, math.Abs(b[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBackendsGemmParity(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	native, gonum := NativeBackend{}, GonumBackend{}
+
+	sizes := [][3]int{{1, 1, 1}, {1, 7, 3}, {5, 1, 4}, {9, 13, 6}, {70, 130, 300}}
+	for _, sz := range sizes {
+		m, n, k := sz[0], sz[1], sz[2]
+		for _, tr := range [][2]bool{{false, false}, {false, true}, {true, false}, {true, true}} {
+			a := randomTensor(rng, m, k)
+			if tr[0] {
+				a = randomTensor(rng, k, m)
+			}
+			b := randomTensor(rng, k, n)
+			if tr[1] {
+				b = randomTensor(rng, n, k)
+			}
+			c := randomTensor(rng, m, n)
+			want, got := c.Clone(), c.Clone()
+
+			if err := native.Gemm(tr[0], tr[1], 0.7, a, b, 0.3, want); err != nil {
+				t.Fatalf("native Gemm(%v, %v) %v returned error: %v", tr[0], tr[1], sz, err)
+			}
+			if err := gonum.Gemm(tr[0], tr[1], 0.7, a, b, 0.3, got); err != nil {
+				t.Fatalf("gonum Gemm(%v, %v) %v returned error: %v", tr[0], tr[1], sz, err)
+			}
+			if !closeSlices(got.Data(), want.Data()) {
+				t.Errorf("Gemm(%v, %v) %v: gonum and native backends disagree", tr[0], tr[1], sz)
+			}
+		}
+	}
+}
+
+func TestBackendsGemmViews(t *testing.T) {
+	rng := rand.New(rand.NewPCG(5, 6))
+	x := randomTensor(rng, 8, 10)
+	y := randomTensor(rng, 10, 6)
+	cols, _ := x.Slice(1, 2, 7) // 8×5, row stride 10
+	rows, _ := y.Slice(0, 3, 8) // 5×6, contiguous rows
+	step, _ := x.Permute(1, 0)  // 10×8 transpose view
+
+	operands := []struct {
+		name string
+		a, b *Tensor
+	}{
+		{"column slice", cols, rows},
+		{"transpose view", step, x},
+		{"transposed slice", cols.T(), x},
+		{"transposed operands", y.T(), x.T()},
+	}
+	for _, op := range operands {
+		m, n := op.a.Dim(0), op.b.Dim(1)
+		want, got := NewTensor(m, n), NewTensor(m, n)
+		if err := (NativeBackend{}).Gemm(false, false, 1, op.a, op.b, 0, want); err != nil {
+			t.Fatalf("%s: native Gemm returned error: %v", op.name, err)
+		}
+		if err := (GonumBackend{}).Gemm(false, false, 1, op.a, op.b, 0, got); err != nil {
+			t.Fatalf("%s: gonum Gemm returned error: %v", op.name, err)
+		}
+		if !closeSlices(got.Data(), want.Data()) {
+			t.Errorf("%s: gonum and native backends disagree", op.name)
+		}
+	}
+
+	// beta 0 must not read the destination, and k = 0 leaves beta·C.
+	for _, b := range []Backend{NativeBackend{}, GonumBackend{}} {
+		c, _ := FromMatrix([][]float64{{math.NaN()}})
+		one, _ := FromMatrix([][]float64{{2}})
+		if err := b.Gemm(false, false, 1, one, one, 0, c); err != nil || c.At(0, 0) != 4 {
+			t.Errorf("%s Gemm with beta 0 over NaN = %v, %v; want 4", b.Name(), c.At(0, 0), err)
+		}
+		c.Fill(3)
+		if err := b.Gemm(false, false, 1, NewTensor(1, 0), NewTensor(0, 1), 2, c); err != nil || c.At(0, 0) != 6 {
+			t.Errorf("%s Gemm with k = 0 = %v, %v; want 6", b.Name(), c.At(0, 0), err)
+		}
+	}
+}
+
+func TestBackendsVectorParity(t *testing.T) {
+	rng := rand.New(rand.NewPCG(7, 8))
+	x, y := make([]float64, 37), make([]float64, 37)
+	for i := range x {
+		x[i], y[i] = rng.NormFloat64(), rng.NormFloat64()
+	}
+	native, gonum := NativeBackend{}, GonumBackend{}
+
+	if a, b := native.Dot(x, y), gonum.Dot(x, y); !closeSlices([]float64{b}, []float64{a}) {
+		t.Errorf("Dot: gonum %v, native %v", b, a)
+	}
+	if a, b := native.Nrm2(x), gonum.Nrm2(x); !closeSlices([]float64{b}, []float64{a}) {
+		t.Errorf("Nrm2: gonum %v, native %v", b, a)
+	}
+
+	yn, yg := append([]float64(nil), y...), append([]float64(nil), y...)
+	native.Axpy(-0.5, x, yn)
+	gonum.Axpy(-0.5, x, yg)
+	if !closeSlices(yg, yn) {
+		t.Errorf("Axpy: gonum %v, native %v", yg, yn)
+	}
+
+	native.Scal(3, yn)
+	gonum.Scal(3, yg)
+	if !closeSlices(yg, yn) {
+		t.Errorf("Scal: gonum %v, native %v", yg, yn)
+	}
+	nan := []float64{math.NaN()}
+	native.Scal(0, nan)
+	if nan[0] != 0 {
+		t.Errorf("native Scal(0, NaN) = %v; want 0 as in BLAS", nan[0])
+	}
+
+	m := randomTensor(rng, 7, 4)
+	if a, b := native.Transpose(m), gonum.Transpose(m); !reflect.DeepEqual(b.ToMatrix(), a.ToMatrix()) {
+		t.Errorf("Transpose: gonum %v, native %v", b.ToMatrix(), a.ToMatrix())
+	}
+	if got := gonum.Transpose(m.T()); !reflect.DeepEqual(got.ToMatrix(), m.ToMatrix()) {
+		t.Error("gonum Transpose of a transpose view did not restore the matrix")
+	}
+}
+
+func TestNumGoBackendSelection(t *testing.T) {
+	defer SetDefaultBackend(nil)
+
+	for _, name := range []string{"native", "gonum"} {
+		b, err := NewBackend(name)
+		if err != nil {
+			t.Fatalf("NewBackend(%q) returned error: %v", name, err)
+		}
+		if b.Name() != name {
+			t.Errorf("NewBackend(%q).Name() = %q", name, b.Name())
+		}
+
+		// The same NumGo calls must agree whichever backend serves them.
+		for _, ng := range []*NumGo{{Backend: b}, {}} {
+			SetDefaultBackend(b)
+			C, err := ng.DotMatrix([][]float64{{1, 2}, {3, 4}}, [][]float64{{5, 6}, {7, 8}})
+			if err != nil || !reflect.DeepEqual(C, [][]float64{{19, 22}, {43, 50}}) {
+				t.Errorf("%s DotMatrix() = %v, %v", name, C, err)
+			}
+			if T := ng.Transpose([][]float64{{1, 2, 3}, {4, 5, 6}}); !reflect.DeepEqual(T, [][]float64{{1, 4}, {2, 5}, {3, 6}}) {
+				t.Errorf("%s Transpose() = %v", name, T)
+			}
+			if n := ng.Norm([]float64{3, 4}); n != 5 {
+				t.Errorf("%s Norm() = %v; want 5", name, n)
+			}
+			if d, _ := ng.SubVectors([]float64{1, 2}, []float64{3, 5}); !reflect.DeepEqual(d, []float64{-2, -3}) {
+				t.Errorf("%s SubVectors() = %v", name, d)
+			}
+		}
+	}
+
+	if _, err := NewBackend("cuda"); err == nil {
+		t.Error("NewBackend(\"cuda\") expected error, got nil")
+	}
+	SetDefaultBackend(nil)
+	if DefaultBackend().Name() != "native" {
+		t.Errorf("SetDefaultBackend(nil) left %q as default", DefaultBackend().Name())
+	}
+}
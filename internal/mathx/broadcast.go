@@ -0,0 +1,226 @@
+package mathx
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+//? --------------------
+//? Broadcasting
+//? --------------------
+
+// BroadcastShapes returns the shape two operands broadcast to under NumPy
+// rules: shapes are aligned on their trailing dimensions, missing leading
+// dimensions count as 1, and each aligned pair must be equal or contain a 1.
+func BroadcastShapes(a, b []int) ([]int, error) {
+	n := max(len(a), len(b))
+	out := make([]int, n)
+	for k := 1; k <= n; k++ {
+		da, db := 1, 1
+		if k <= len(a) {
+			da = a[len(a)-k]
+		}
+		if k <= len(b) {
+			db = b[len(b)-k]
+		}
+		switch {
+		case da == db, db == 1:
+			out[n-k] = da
+		case da == 1:
+			out[n-k] = db
+		default:
+			return nil, fmt.Errorf("shapes %v and %v cannot be broadcast together", a, b)
+		}
+	}
+	return out, nil
+}
+
+// BroadcastTo returns a view of t expanded to shape without copying. Expanded
+// dimensions get stride 0, so every index along them reads the same element;
+// treat the view as read-only.
+func (t *Tensor) BroadcastTo(shape ...int) (*Tensor, error) {
+	if len(shape) < len(t.shape) {
+		return nil, fmt.Errorf("cannot broadcast shape %v to %v", t.shape, shape)
+	}
+	lead := len(shape) - len(t.shape)
+	view := &Tensor{
+		data:    t.data,
+		shape:   append([]int(nil), shape...),
+		strides: make([]int, len(shape)),
+		offset:  t.offset,
+	}
+	for k, d := range t.shape {
+		switch d {
+		case shape[lead+k]:
+			view.strides[lead+k] = t.strides[k]
+		case 1:
+			// stride 0 repeats the single element
+		default:
+			return nil, fmt.Errorf("cannot broadcast shape %v to %v", t.shape, shape)
+		}
+	}
+	return view, nil
+}
+
+// Scalar returns a 0-dimensional tensor holding v, which broadcasts against
+// any shape.
+func Scalar(v float64) *Tensor {
+	t := NewTensor()
+	t.data[0] = v
+	return t
+}
+
+// eachPair calls fn with the storage positions of corresponding elements of
+// a and b, which must have the same shape, in row-major order.
+func eachPair(a, b *Tensor, fn func(pa, pb int)) {
+	size := a.Size()
+	if size == 0 {
+		return
+	}
+	if a.IsContiguous() && b.IsContiguous() {
+		for i := 0; i < size; i++ {
+			fn(a.offset+i, b.offset+i)
+		}
+		return
+	}
+
+	idx := make([]int, len(a.shape))
+	pa, pb := a.offset, b.offset
+	for n := 0; n < size; n++ {
+		fn(pa, pb)
+		for k := len(idx) - 1; k >= 0; k-- {
+			idx[k]++
+			pa += a.strides[k]
+			pb += b.strides[k]
+			if idx[k] < a.shape[k] {
+				break
+			}
+			pa -= idx[k] * a.strides[k]
+			pb -= idx[k] * b.strides[k]
+			idx[k] = 0
+		}
+	}
+}
+
+//? --------------------
+//? Elementwise Operations
+//? --------------------
+
+// Broadcast returns f(a, b) evaluated elementwise after broadcasting a and b
+// to a common shape. The named operations below are built on it.
+func (ng *NumGo) Broadcast(a, b *Tensor, f func(x, y float64) float64) (*Tensor, error) {
+	if a == nil || b == nil {
+		return nil, errors.New("nil tensor")
+	}
+	shape, err := BroadcastShapes(a.shape, b.shape)
+	if err != nil {
+		return nil, err
+	}
+	av, err := a.BroadcastTo(shape...)
+	if err != nil {
+		return nil, err
+	}
+	bv, err := b.BroadcastTo(shape...)
+	if err != nil {
+		return nil, err
+	}
+
+	// out is contiguous, so visiting the views in row-major order fills it in order.
+	out := NewTensor(shape...)
+	i := 0
+	eachPair(av, bv, func(pa, pb int) {
+		out.data[i] = f(a.data[pa], b.data[pb])
+		i++
+	})
+	return out, nil
+}
+
+// binary runs Broadcast and prefixes errors with the operation name.
+func (ng *NumGo) binary(name string, a, b *Tensor, f func(x, y float64) float64) (*Tensor, error) {
+	out, err := ng.Broadcast(a, b, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return out, nil
+}
+
+// Add returns a + b with broadcasting (like np.add).
+func (ng *NumGo) Add(a, b *Tensor) (*Tensor, error) {
+	return ng.binary("Add", a, b, func(x, y float64) float64 { return x + y })
+}
+
+// Sub returns a - b with broadcasting.
+func (ng *NumGo) Sub(a, b *Tensor) (*Tensor, error) {
+	return ng.binary("Sub", a, b, func(x, y float64) float64 { return x - y })
+}
+
+// Mul returns the elementwise product a ⊙ b with broadcasting.
+func (ng *NumGo) Mul(a, b *Tensor) (*Tensor, error) {
+	return ng.binary("Mul", a, b, func(x, y float64) float64 { return x * y })
+}
+
+// Div returns a / b with broadcasting. Division by zero follows IEEE 754
+// (±Inf or NaN), as in NumPy.
+func (ng *NumGo) Div(a, b *Tensor) (*Tensor, error) {
+	return ng.binary("Div", a, b, func(x, y float64) float64 { return x / y })
+}
+
+// Pow returns a raised to the power b, elementwise with broadcasting.
+func (ng *NumGo) Pow(a, b *Tensor) (*Tensor, error) {
+	return ng.binary("Pow", a, b, math.Pow)
+}
+
+// Maximum returns the elementwise maximum of a and b (like np.maximum).
+func (ng *NumGo) Maximum(a, b *Tensor) (*Tensor, error) {
+	return ng.binary("Maximum", a, b, math.Max)
+}
+
+// Minimum returns the elementwise minimum of a and b (like np.minimum).
+func (ng *NumGo) Minimum(a, b *Tensor) (*Tensor, error) {
+	return ng.binary("Minimum", a, b, math.Min)
+}
+
+//? --------------------
+//? Comparisons
+//? --------------------
+
+// indicator converts a comparison result to 1 or 0. Comparisons return 1
+// where the relation holds and 0 elsewhere, so their result can be used
+// directly as a mask with Mul.
+func indicator(ok bool) float64 {
+	if ok {
+		return 1
+	}
+	return 0
+}
+
+// Equal returns a == b elementwise with broadcasting.
+func (ng *NumGo) Equal(a, b *Tensor) (*Tensor, error) {
+	return ng.binary("Equal", a, b, func(x, y float64) float64 { return indicator(x == y) })
+}
+
+// NotEqual returns a != b elementwise with broadcasting.
+func (ng *NumGo) NotEqual(a, b *Tensor) (*Tensor, error) {
+	return ng.binary("NotEqual", a, b, func(x, y float64) float64 { return indicator(x != y) })
+}
+
+// Greater returns a > b elementwise with broadcasting.
+func (ng *NumGo) Greater(a, b *Tensor) (*Tensor, error) {
+	return ng.binary("Greater", a, b, func(x, y float64) float64 { return indicator(x > y) })
+}
+
+// GreaterEqual returns a >= b elementwise with broadcasting.
+func (ng *NumGo) GreaterEqual(a, b *Tensor) (*Tensor, error) {
+	return ng.binary("GreaterEqual", a, b, func(x, y float64) float64 { return indicator(x >= y) })
+}
+
+// Less returns a < b elementwise with broadcasting.
+func (ng *NumGo) Less(a, b *Tensor) (*Tensor, error) {
+	return ng.binary("Less", a, b, func(x, y float64) float64 { return indicator(x < y) })
+}
+
+// LessEqual returns a <= b elementwise with broadcasting.
+func (ng *NumGo) LessEqual(a, b *Tensor) (*Tensor, error) {
+	return ng.binary("LessEqual", a, b, func(x, y float64) float64 { return indicator(x <= y) })
+}
@@ -0,0 +1,122 @@
+package mathx
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestBroadcastShapes(t *testing.T) {
+	tests := []struct {
+		a, b, want []int
+	}{
+		{[]int{2, 3}, []int{3}, []int{2, 3}},
+		{[]int{4, 1, 5}, []int{3, 1}, []int{4, 3, 5}},
+		{[]int{2, 1}, []int{1, 3}, []int{2, 3}},
+		{[]int{}, []int{2, 2}, []int{2, 2}},
+		{[]int{0, 1}, []int{1, 4}, []int{0, 4}},
+	}
+	for _, tt := range tests {
+		got, err := BroadcastShapes(tt.a, tt.b)
+		if err != nil {
+			t.Errorf("BroadcastShapes(%v, %v) returned error: %v", tt.a, tt.b, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("BroadcastShapes(%v, %v) = %v; want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if _, err := BroadcastShapes([]int{2, 3}, []int{2}); err == nil {
+		t.Error("BroadcastShapes([2 3], [2]) expected error, got nil")
+	}
+}
+
+func TestBroadcastArithmetic(t *testing.T) {
+	ng := NumGo{}
+	x, _ := FromSlice([]float64{1, 2, 3, 4, 5, 6}, 2, 3)
+	row, _ := FromSlice([]float64{10, 20, 30}, 3)
+	col, _ := FromSlice([]float64{2, 4}, 2, 1)
+
+	tests := []struct {
+		name string
+		op   func(a, b *Tensor) (*Tensor, error)
+		a, b *Tensor
+		want [][]float64
+	}{
+		{"Add row", ng.Add, x, row, [][]float64{{11, 22, 33}, {14, 25, 36}}},
+		{"Sub col", ng.Sub, x, col, [][]float64{{-1, 0, 1}, {0, 1, 2}}},
+		{"Mul scalar", ng.Mul, x, Scalar(2), [][]float64{{2, 4, 6}, {8, 10, 12}}},
+		{"Div col", ng.Div, x, col, [][]float64{{0.5, 1, 1.5}, {1, 1.25, 1.5}}},
+		{"Pow", ng.Pow, x, Scalar(2), [][]float64{{1, 4, 9}, {16, 25, 36}}},
+		{"Maximum", ng.Maximum, x, Scalar(3), [][]float64{{3, 3, 3}, {4, 5, 6}}},
+		{"Minimum", ng.Minimum, x, Scalar(3), [][]float64{{1, 2, 3}, {3, 3, 3}}},
+		{"outer", ng.Mul, col, row, [][]float64{{20, 40, 60}, {40, 80, 120}}},
+		// Transposed views broadcast like any other tensor.
+		{"Add view", ng.Add, x.T(), col.T(), [][]float64{{3, 8}, {4, 9}, {5, 10}}},
+	}
+	for _, tt := range tests {
+		got, err := tt.op(tt.a, tt.b)
+		if err != nil {
+			t.Errorf("%s returned error: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.ToMatrix(), tt.want) {
+			t.Errorf("%s = %v; want %v", tt.name, got.ToMatrix(), tt.want)
+		}
+	}
+
+	if _, err := ng.Add(x, col.T()); err == nil {
+		t.Error("Add([2 3], [1 2]) expected error, got nil")
+	}
+	if got, _ := ng.Div(Scalar(1), Scalar(0)); !math.IsInf(got.At(), 1) {
+		t.Errorf("Div(1, 0) = %v; want +Inf", got.At())
+	}
+}
+
+func TestBroadcastComparisons(t *testing.T) {
+	ng := NumGo{}
+	x, _ := FromSlice([]float64{1, 2, 3}, 3)
+	two := Scalar(2)
+
+	tests := []struct {
+		name string
+		op   func(a, b *Tensor) (*Tensor, error)
+		want []float64
+	}{
+		{"Equal", ng.Equal, []float64{0, 1, 0}},
+		{"NotEqual", ng.NotEqual, []float64{1, 0, 1}},
+		{"Greater", ng.Greater, []float64{0, 0, 1}},
+		{"GreaterEqual", ng.GreaterEqual, []float64{0, 1, 1}},
+		{"Less", ng.Less, []float64{1, 0, 0}},
+		{"LessEqual", ng.LessEqual, []float64{1, 1, 0}},
+	}
+	for _, tt := range tests {
+		got, err := tt.op(x, two)
+		if err != nil {
+			t.Errorf("%s returned error: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.Data(), tt.want) {
+			t.Errorf("%s(x, 2) = %v; want %v", tt.name, got.Data(), tt.want)
+		}
+	}
+}
+
+func TestBroadcastToSharesStorage(t *testing.T) {
+	row, _ := FromSlice([]float64{1, 2, 3}, 3)
+	view, err := row.BroadcastTo(2, 3)
+	if err != nil {
+		t.Fatalf("BroadcastTo() returned error: %v", err)
+	}
+	if !reflect.DeepEqual(view.Strides(), []int{0, 1}) {
+		t.Errorf("BroadcastTo() strides = %v; want [0 1]", view.Strides())
+	}
+	row.Set(9, 0)
+	if want := [][]float64{{9, 2, 3}, {9, 2, 3}}; !reflect.DeepEqual(view.ToMatrix(), want) {
+		t.Errorf("BroadcastTo() view = %v; want %v", view.ToMatrix(), want)
+	}
+	if _, err := row.BroadcastTo(3, 2); err == nil {
+		t.Error("BroadcastTo(3, 2) expected error, got nil")
+	}
+}
@@ -0,0 +1,185 @@
+package mathx
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+//? --------------------
+//? GEMM
+//? --------------------
+
+// Tile sizes of the blocked kernel. A tileK×tileN panel of B (256 KiB) stays
+// in L2 while the tileM rows of C it updates are accumulated.
+const (
+	tileM = 64
+	tileN = 256
+	tileK = 128
+)
+
+// parallelThreshold is the m·n·k below which Gemm runs on the calling
+// goroutine, where spawning workers would cost more than it saves.
+const parallelThreshold = 1 << 16
+
+// gemmWorkers is the number of goroutines a large product is spread over.
+var gemmWorkers = runtime.NumCPU()
+
+// Gemm computes the general matrix product
+//
+//	C = alpha·op(A)·op(B) + beta·C
+//
+// where op(X) is X, or Xᵀ when the matching trans flag is set, as in BLAS
+// dgemm. op(A) must be m×k, op(B) k×n and c a contiguous m×n tensor that
+// does not share storage with a or b. When beta is 0, C is overwritten
+// without being read.
+//
+// Gemm runs on DefaultBackend.
+func Gemm(transA, transB bool, alpha float64, a, b *Tensor, beta float64, c *Tensor) error {
+	return DefaultBackend().Gemm(transA, transB, alpha, a, b, beta, c)
+}
+
+// gemmNative is the Gemm of NativeBackend. C is split into cache-sized tiles
+// that are spread over one goroutine per CPU. Every element is accumulated
+// by one goroutine in order of increasing k, so the result does not depend
+// on the number of workers.
+func gemmNative(transA, transB bool, alpha float64, a, b *Tensor, beta float64, c *Tensor) error {
+	if a == nil || b == nil || c == nil {
+		return errors.New("Gemm: nil tensor")
+	}
+	if a.Dims() != 2 || b.Dims() != 2 || c.Dims() != 2 {
+		return fmt.Errorf("Gemm needs 2-D tensors, got shapes %v, %v and %v", a.shape, b.shape, c.shape)
+	}
+	opA, opB := a, b
+	if transA {
+		opA = a.T()
+	}
+	if transB {
+		opB = b.T()
+	}
+	m, k, n := opA.shape[0], opA.shape[1], opB.shape[1]
+	if opB.shape[0] != k {
+		return fmt.Errorf("Gemm: incompatible shapes %v and %v", opA.shape, opB.shape)
+	}
+	if c.shape[0] != m || c.shape[1] != n || !c.IsContiguous() {
+		return fmt.Errorf("Gemm: destination must be a contiguous %d×%d tensor, got %v", m, n, c.shape)
+	}
+
+	out := c.Data()
+	switch beta {
+	case 0:
+		for i := range out {
+			out[i] = 0
+		}
+	case 1:
+	default:
+		for i := range out {
+			out[i] *= beta
+		}
+	}
+	if alpha == 0 || m == 0 || n == 0 || k == 0 {
+		return nil
+	}
+
+	// The kernel walks op(A) and op(B) along rows. Row-major operands are
+	// used in place; strided views such as a transpose are packed once, which
+	// costs O(mk + kn) against the O(mnk) product.
+	g := &gemm{
+		m: m, n: n, k: k,
+		alpha: alpha,
+		a:     opA.Contiguous().Data(),
+		b:     opB.Contiguous().Data(),
+		c:     out,
+	}
+	g.run()
+	return nil
+}
+
+// gemm holds the row-major operands of one product.
+type gemm struct {
+	m, n, k int
+	alpha   float64
+	a, b, c []float64
+}
+
+// run computes every tile of C, in parallel when the product is large enough.
+func (g *gemm) run() {
+	colTiles := (g.n + tileN - 1) / tileN
+	tiles := (g.m + tileM - 1) / tileM * colTiles
+	workers := min(gemmWorkers, tiles)
+	if workers <= 1 || g.m*g.n*g.k < parallelThreshold {
+		for t := 0; t < tiles; t++ {
+			g.tile(t, colTiles)
+		}
+		return
+	}
+
+	// Tiles of C are disjoint, so workers never write the same element.
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for t := w; t < tiles; t += workers {
+				g.tile(t, colTiles)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// tile accumulates tile t of C, stepping through k in tileK panels.
+func (g *gemm) tile(t, colTiles int) {
+	i0, j0 := t/colTiles*tileM, t%colTiles*tileN
+	i1, j1 := min(i0+tileM, g.m), min(j0+tileN, g.n)
+	for p0 := 0; p0 < g.k; p0 += tileK {
+		p1 := min(p0+tileK, g.k)
+		i := i0
+		for ; i+4 <= i1; i += 4 {
+			g.kernel4(i, j0, j1, p0, p1)
+		}
+		for ; i < i1; i++ {
+			g.kernel1(i, j0, j1, p0, p1)
+		}
+	}
+}
+
+// kernel4 updates rows i..i+3 of C over columns [j0, j1) with the panel
+// p0 ≤ p < p1, loading every element of B once for all four rows.
+func (g *gemm) kernel4(i, j0, j1, p0, p1 int) {
+	n, k := g.n, g.k
+	c0 := g.c[i*n+j0 : i*n+j1]
+	c1 := g.c[(i+1)*n+j0 : (i+1)*n+j1]
+	c2 := g.c[(i+2)*n+j0 : (i+2)*n+j1]
+	c3 := g.c[(i+3)*n+j0 : (i+3)*n+j1]
+	for p := p0; p < p1; p++ {
+		a0 := g.alpha * g.a[i*k+p]
+		a1 := g.alpha * g.a[(i+1)*k+p]
+		a2 := g.alpha * g.a[(i+2)*k+p]
+		a3 := g.alpha * g.a[(i+3)*k+p]
+		bp := g.b[p*n+j0 : p*n+j1]
+		// Equal lengths let the compiler drop the bounds checks below.
+		c0, c1, c2, c3 = c0[:len(bp)], c1[:len(bp)], c2[:len(bp)], c3[:len(bp)]
+		for j, bv := range bp {
+			c0[j] += a0 * bv
+			c1[j] += a1 * bv
+			c2[j] += a2 * bv
+			c3[j] += a3 * bv
+		}
+	}
+}
+
+// kernel1 is kernel4 for a single leftover row.
+func (g *gemm) kernel1(i, j0, j1, p0, p1 int) {
+	n, k := g.n, g.k
+	ci := g.c[i*n+j0 : i*n+j1]
+	for p := p0; p < p1; p++ {
+		ap := g.alpha * g.a[i*k+p]
+		bp := g.b[p*n+j0 : p*n+j1]
+		ci = ci[:len(bp)]
+		for j, bv := range bp {
+			ci[j] += ap * bv
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package mathx
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// randomTensor returns a rows×cols tensor of values drawn from U(-1, 1).
+func randomTensor(rng *rand.Rand, rows, cols int) *Tensor {
+	t := NewTensor(rows, cols)
+	t.Apply(func(float64) float64 { return 2*rng.Float64() - 1 })
+	return t
+}
+
+// naiveGemm is the textbook i-j-p triple loop over row-major data, used as
+// the reference result and as the baseline in the benchmarks.
+func naiveGemm(transA, transB bool, alpha float64, a, b *Tensor, beta float64, c *Tensor) {
+	if transA {
+		a = a.T()
+	}
+	if transB {
+		b = b.T()
+	}
+	m, k, n := a.Dim(0), a.Dim(1), b.Dim(1)
+	ad, bd, cd := a.Contiguous().Data(), b.Contiguous().Data(), c.Data()
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			var sum float64
+			for p := 0; p < k; p++ {
+				sum += ad[i*k+p] * bd[p*n+j]
+			}
+			cd[i*n+j] = alpha*sum + beta*cd[i*n+j]
+		}
+	}
+}
+
+func TestGemmMatchesNaive(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	defer func(w int) { gemmWorkers = w }(gemmWorkers)
+
+	// The sizes cross the tile edges and the parallel threshold.
+	sizes := [][3]int{{1, 1, 1}, {3, 5, 2}, {7, 300, 9}, {70, 130, 300}, {130, 270, 65}}
+	for _, workers := range []int{1, 4} {
+		gemmWorkers = workers
+		for _, sz := range sizes {
+			m, n, k := sz[0], sz[1], sz[2]
+			for _, tr := range [][2]bool{{false, false}, {false, true}, {true, false}, {true, true}} {
+				a := randomTensor(rng, m, k)
+				if tr[0] {
+					a = randomTensor(rng, k, m)
+				}
+				b := randomTensor(rng, k, n)
+				if tr[1] {
+					b = randomTensor(rng, n, k)
+				}
+				c := randomTensor(rng, m, n)
+				want := c.Clone()
+
+				naiveGemm(tr[0], tr[1], 0.5, a, b, -2, want)
+				if err := Gemm(tr[0], tr[1], 0.5, a, b, -2, c); err != nil {
+					t.Fatalf("Gemm(%v, %v) %v returned error: %v", tr[0], tr[1], sz, err)
+				}
+				got, exp := c.Data(), want.Data()
+				for i := range exp {
+					if math.Abs(got[i]-exp[i]) > 1e-9 {
+						t.Errorf("Gemm(%v, %v) %v with %d workers: c[%d] = %v; want %v",
+							tr[0], tr[1], sz, workers, i, got[i], exp[i])
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestGemmBetaZeroIgnoresDestination(t *testing.T) {
+	a, _ := FromMatrix([][]float64{{1, 2}, {3, 4}})
+	c, _ := FromMatrix([][]float64{{math.NaN(), math.Inf(1)}, {1, 1}})
+	if err := Gemm(false, true, 1, a, a, 0, c); err != nil {
+		t.Fatalf("Gemm() returned error: %v", err)
+	}
+	if want := [][]float64{{5, 11}, {11, 25}}; fmt.Sprint(c.ToMatrix()) != fmt.Sprint(want) {
+		t.Errorf("Gemm(A·Aᵀ) = %v; want %v", c.ToMatrix(), want)
+	}
+
+	if err := Gemm(false, false, 1, a, NewTensor(3, 2), 0, c); err == nil {
+		t.Error("Gemm() expected error on incompatible shapes, got nil")
+	}
+	if err := Gemm(false, false, 1, a, a, 0, c.T()); err == nil {
+		t.Error("Gemm() expected error on a non-contiguous destination, got nil")
+	}
+}
+
+// BenchmarkGemm compares the tiled, parallel native Gemm and the gonum
+// backend with the naive triple loop on square products and on the shapes of a 784→512 dense layer with a batch
+// of 256: forward (X·Wᵀ), weight gradient (dYᵀ·X) and input gradient (dY·W).
+func BenchmarkGemm(b *testing.B) {
+	cases := []struct {
+		name           string
+		transA, transB bool
+		m, n, k        int
+	}{
+		{"square128", false, false, 128, 128, 128},
+		{"square512", false, false, 512, 512, 512},
+		{"dense_forward", false, true, 256, 512, 784},
+		{"dense_dweights", true, false, 512, 784, 256},
+		{"dense_dinputs", false, false, 256, 784, 512},
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	for _, tc := range cases {
+		rowsA, colsA := tc.m, tc.k
+		if tc.transA {
+			rowsA, colsA = colsA, rowsA
+		}
+		rowsB, colsB := tc.k, tc.n
+		if tc.transB {
+			rowsB, colsB = colsB, rowsB
+		}
+		x, y := randomTensor(rng, rowsA, colsA), randomTensor(rng, rowsB, colsB)
+		c := NewTensor(tc.m, tc.n)
+
+		b.Run(tc.name+"/naive", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				naiveGemm(tc.transA, tc.transB, 1, x, y, 0, c)
+			}
+		})
+		for _, backend := range []Backend{NativeBackend{}, GonumBackend{}} {
+			b.Run(tc.name+"/"+backend.Name(), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if err := backend.Gemm(tc.transA, tc.transB, 1, x, y, 0, c); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
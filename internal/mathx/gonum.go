@@ -0,0 +1,128 @@
+package mathx
+
+import (
+	"errors"
+	"fmt"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+//? --------------------
+//? Gonum Backend
+//? --------------------
+
+// GonumBackend dispatches to gonum's blas64 package, which runs gonum's
+// pure-Go BLAS unless blas64.Use installed another implementation.
+type GonumBackend struct{}
+
+// Name implements Backend.
+func (GonumBackend) Name() string { return "gonum" }
+
+// Gemm implements Backend with blas64.Gemm. Transposed and row-sliced views
+// are passed to BLAS as they are; other strided views are packed first.
+func (GonumBackend) Gemm(transA, transB bool, alpha float64, a, b *Tensor, beta float64, c *Tensor) error {
+	if a == nil || b == nil || c == nil {
+		return errors.New("Gemm: nil tensor")
+	}
+	if a.Dims() != 2 || b.Dims() != 2 || c.Dims() != 2 {
+		return fmt.Errorf("Gemm needs 2-D tensors, got shapes %v, %v and %v", a.shape, b.shape, c.shape)
+	}
+	opA, opB := a, b
+	if transA {
+		opA = a.T()
+	}
+	if transB {
+		opB = b.T()
+	}
+	m, k, n := opA.shape[0], opA.shape[1], opB.shape[1]
+	if opB.shape[0] != k {
+		return fmt.Errorf("Gemm: incompatible shapes %v and %v", opA.shape, opB.shape)
+	}
+	if c.shape[0] != m || c.shape[1] != n || !c.IsContiguous() {
+		return fmt.Errorf("Gemm: destination must be a contiguous %d×%d tensor, got %v", m, n, c.shape)
+	}
+	if m == 0 || n == 0 {
+		return nil
+	}
+	if k == 0 {
+		// BLAS rejects zero-width operands; the product is empty, so only beta applies.
+		GonumBackend{}.Scal(beta, c.Data())
+		return nil
+	}
+
+	ga, ta := general(opA)
+	gb, tb := general(opB)
+	gc := blas64.General{Rows: m, Cols: n, Stride: n, Data: c.Data()}
+	blas64.Gemm(ta, tb, alpha, ga, gb, beta, gc)
+	return nil
+}
+
+// general describes the non-empty matrix t to BLAS as a row-major
+// blas64.General and whether BLAS must transpose it.
+func general(t *Tensor) (blas64.General, blas.Transpose) {
+	rows, cols := t.shape[0], t.shape[1]
+	data := t.data[t.offset:]
+	switch {
+	case t.strides[1] == 1 && (rows == 1 || t.strides[0] >= cols):
+		stride := t.strides[0]
+		if rows == 1 {
+			stride = cols
+		}
+		return blas64.General{Rows: rows, Cols: cols, Stride: stride, Data: data}, blas.NoTrans
+	case t.strides[0] == 1 && (cols == 1 || t.strides[1] >= rows):
+		// t is the transpose of a row-major matrix.
+		stride := t.strides[1]
+		if cols == 1 {
+			stride = rows
+		}
+		return blas64.General{Rows: cols, Cols: rows, Stride: stride, Data: data}, blas.Trans
+	default:
+		packed := t.Clone()
+		return blas64.General{Rows: rows, Cols: cols, Stride: cols, Data: packed.data}, blas.NoTrans
+	}
+}
+
+// Transpose implements Backend by copying every column of t into a row of
+// the result with blas64.Copy.
+func (GonumBackend) Transpose(t *Tensor) *Tensor {
+	src := t.Contiguous()
+	rows, cols := src.shape[0], src.shape[1]
+	out := NewTensor(cols, rows)
+	if rows == 0 {
+		return out
+	}
+	data := src.data[src.offset:]
+	for j := 0; j < cols; j++ {
+		blas64.Copy(
+			blas64.Vector{N: rows, Inc: cols, Data: data[j:]},
+			blas64.Vector{N: rows, Inc: 1, Data: out.data[j*rows:]},
+		)
+	}
+	return out
+}
+
+// Dot implements Backend.
+func (GonumBackend) Dot(x, y []float64) float64 {
+	return blas64.Dot(vector(x), vector(y))
+}
+
+// Axpy implements Backend.
+func (GonumBackend) Axpy(alpha float64, x, y []float64) {
+	blas64.Axpy(alpha, vector(x), vector(y))
+}
+
+// Scal implements Backend.
+func (GonumBackend) Scal(alpha float64, x []float64) {
+	blas64.Scal(alpha, vector(x))
+}
+
+// Nrm2 implements Backend.
+func (GonumBackend) Nrm2(x []float64) float64 {
+	return blas64.Nrm2(vector(x))
+}
+
+// vector wraps a slice as a unit-stride blas64.Vector.
+func vector(x []float64) blas64.Vector {
+	return blas64.Vector{N: len(x), Inc: 1, Data: x}
+}
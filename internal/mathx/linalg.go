@@ -0,0 +1,751 @@
+package mathx
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// epsilon is the float64 machine epsilon, the spacing of values near 1.
+const epsilon = 0x1p-52
+
+// maxSweeps bounds the Jacobi iterations of SVD and EigenSym; both converge
+// quadratically and normally need fewer than 10 sweeps.
+const maxSweeps = 100
+
+// Eye returns the n×n identity matrix.
+func Eye(n int) *Tensor {
+	t := NewTensor(n, n)
+	for i := 0; i < n; i++ {
+		t.data[i*n+i] = 1
+	}
+	return t
+}
+
+// square checks that a is a square matrix and returns its order.
+func square(name string, a *Tensor) (int, error) {
+	if a == nil || a.Dims() != 2 {
+		return 0, fmt.Errorf("%s: need a matrix", name)
+	}
+	if a.Dim(0) != a.Dim(1) {
+		return 0, fmt.Errorf("%s: matrix must be square, got shape %v", name, a.shape)
+	}
+	return a.Dim(0), nil
+}
+
+// rhs copies the right-hand side of a system with n equations into an n×k
+// matrix and reports whether it was a vector, so the solution can take the
+// same form.
+func rhs(name string, b *Tensor, n int) (*Tensor, bool, error) {
+	switch {
+	case b == nil:
+		return nil, false, fmt.Errorf("%s: nil right-hand side", name)
+	case b.Dims() == 1 && b.Dim(0) == n:
+		x, err := b.Clone().Reshape(n, 1)
+		return x, true, err
+	case b.Dims() == 2 && b.Dim(0) == n:
+		return b.Clone(), false, nil
+	default:
+		return nil, false, fmt.Errorf("%s: right-hand side has shape %v, expected %d rows", name, b.shape, n)
+	}
+}
+
+// solution returns x in the form the right-hand side was given in.
+func solution(x *Tensor, vector bool) *Tensor {
+	if vector {
+		x, _ = x.Reshape(-1)
+	}
+	return x
+}
+
+//? --------------------
+//? LU Decomposition
+//? --------------------
+
+// LU is the factorization P·A = L·U of a square matrix with partial
+// pivoting, where L is unit lower triangular and U upper triangular.
+type LU struct {
+	lu    *Tensor // L strictly below the diagonal, U on and above it
+	pivot []int   // row i of P·A is row pivot[i] of A
+	sign  float64 // det(P), ±1
+}
+
+// LU factorizes a square matrix, always swapping the largest remaining
+// entry of each column onto the diagonal. Singular matrices factorize too;
+// their U has a zero on the diagonal and Solve reports them.
+func (ng *NumGo) LU(a *Tensor) (*LU, error) {
+	n, err := square("LU", a)
+	if err != nil {
+		return nil, err
+	}
+	f := &LU{lu: a.Clone(), pivot: make([]int, n), sign: 1}
+	for i := range f.pivot {
+		f.pivot[i] = i
+	}
+	w := f.lu.Rows()
+
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(w[i][k]) > math.Abs(w[p][k]) {
+				p = i
+			}
+		}
+		if p != k {
+			// Rows alias the storage, so swap contents rather than slices.
+			for j := range w[k] {
+				w[k][j], w[p][j] = w[p][j], w[k][j]
+			}
+			f.pivot[k], f.pivot[p] = f.pivot[p], f.pivot[k]
+			f.sign = -f.sign
+		}
+		if w[k][k] == 0 {
+			continue
+		}
+		for i := k + 1; i < n; i++ {
+			w[i][k] /= w[k][k]
+			for j := k + 1; j < n; j++ {
+				w[i][j] -= w[i][k] * w[k][j]
+			}
+		}
+	}
+	return f, nil
+}
+
+// L returns the unit lower-triangular factor.
+func (f *LU) L() *Tensor {
+	n := f.lu.Dim(0)
+	l := Eye(n)
+	for i, row := range f.lu.Rows() {
+		copy(l.data[i*n:i*n+i], row[:i])
+	}
+	return l
+}
+
+// U returns the upper-triangular factor.
+func (f *LU) U() *Tensor {
+	n := f.lu.Dim(0)
+	u := NewTensor(n, n)
+	for i, row := range f.lu.Rows() {
+		copy(u.data[i*n+i:(i+1)*n], row[i:])
+	}
+	return u
+}
+
+// Pivot returns the row permutation: row i of P·A is row Pivot()[i] of A.
+func (f *LU) Pivot() []int { return append([]int(nil), f.pivot...) }
+
+// Det returns the determinant of A.
+func (f *LU) Det() float64 {
+	det := f.sign
+	for i, row := range f.lu.Rows() {
+		det *= row[i]
+	}
+	return det
+}
+
+// Solve returns x with A·x = b, where b is a vector of length n or an n×k
+// matrix of right-hand sides. It fails if A is singular.
+func (f *LU) Solve(b *Tensor) (*Tensor, error) {
+	n := f.lu.Dim(0)
+	x, vector, err := rhs("LU.Solve", b, n)
+	if err != nil {
+		return nil, err
+	}
+	w := f.lu.Rows()
+	bx := x.Rows()
+
+	// x = P·b
+	src := x.Clone().Rows()
+	for i, p := range f.pivot {
+		copy(bx[i], src[p])
+	}
+	// Forward substitution with the unit diagonal of L.
+	for i := 0; i < n; i++ {
+		for k := 0; k < i; k++ {
+			for j := range bx[i] {
+				bx[i][j] -= w[i][k] * bx[k][j]
+			}
+		}
+	}
+	// Back substitution with U.
+	for i := n - 1; i >= 0; i-- {
+		if w[i][i] == 0 {
+			return nil, errors.New("LU.Solve: matrix is singular")
+		}
+		for k := i + 1; k < n; k++ {
+			for j := range bx[i] {
+				bx[i][j] -= w[i][k] * bx[k][j]
+			}
+		}
+		for j := range bx[i] {
+			bx[i][j] /= w[i][i]
+		}
+	}
+	return solution(x, vector), nil
+}
+
+//? --------------------
+//? Cholesky Decomposition
+//? --------------------
+
+// Cholesky is the factorization A = L·Lᵀ of a symmetric positive-definite
+// matrix, with L lower triangular.
+type Cholesky struct {
+	l *Tensor
+}
+
+// Cholesky factorizes a symmetric positive-definite matrix. Only the lower
+// triangle of a is read, as in LAPACK.
+func (ng *NumGo) Cholesky(a *Tensor) (*Cholesky, error) {
+	n, err := square("Cholesky", a)
+	if err != nil {
+		return nil, err
+	}
+	src := a.Rows()
+	l := NewTensor(n, n)
+	w := l.Rows()
+	for j := 0; j < n; j++ {
+		d := src[j][j]
+		for k := 0; k < j; k++ {
+			d -= w[j][k] * w[j][k]
+		}
+		if d <= 0 || math.IsNaN(d) {
+			return nil, errors.New("Cholesky: matrix is not positive definite")
+		}
+		w[j][j] = math.Sqrt(d)
+		for i := j + 1; i < n; i++ {
+			s := src[i][j]
+			for k := 0; k < j; k++ {
+				s -= w[i][k] * w[j][k]
+			}
+			w[i][j] = s / w[j][j]
+		}
+	}
+	return &Cholesky{l: l}, nil
+}
+
+// L returns the lower-triangular factor.
+func (f *Cholesky) L() *Tensor { return f.l.Clone() }
+
+// Det returns the determinant of A, the squared product of the diagonal of L.
+func (f *Cholesky) Det() float64 {
+	det := 1.0
+	for i, row := range f.l.Rows() {
+		det *= row[i]
+	}
+	return det * det
+}
+
+// Solve returns x with A·x = b, where b is a vector or an n×k matrix.
+func (f *Cholesky) Solve(b *Tensor) (*Tensor, error) {
+	n := f.l.Dim(0)
+	x, vector, err := rhs("Cholesky.Solve", b, n)
+	if err != nil {
+		return nil, err
+	}
+	w, bx := f.l.Rows(), x.Rows()
+	// L·y = b
+	for i := 0; i < n; i++ {
+		for k := 0; k < i; k++ {
+			for j := range bx[i] {
+				bx[i][j] -= w[i][k] * bx[k][j]
+			}
+		}
+		for j := range bx[i] {
+			bx[i][j] /= w[i][i]
+		}
+	}
+	// Lᵀ·x = y
+	for i := n - 1; i >= 0; i-- {
+		for k := i + 1; k < n; k++ {
+			for j := range bx[i] {
+				bx[i][j] -= w[k][i] * bx[k][j]
+			}
+		}
+		for j := range bx[i] {
+			bx[i][j] /= w[i][i]
+		}
+	}
+	return solution(x, vector), nil
+}
+
+//? --------------------
+//? QR Decomposition
+//? --------------------
+
+// QR is the reduced factorization A = Q·R of an m×n matrix: with
+// k = min(m, n), Q is m×k with orthonormal columns and R is k×n upper
+// triangular.
+type QR struct {
+	q, r *Tensor
+}
+
+// QR factorizes a matrix with Householder reflections, which keeps Q
+// orthonormal to working precision even for ill-conditioned input.
+func (ng *NumGo) QR(a *Tensor) (*QR, error) {
+	if a == nil || a.Dims() != 2 {
+		return nil, errors.New("QR: need a matrix")
+	}
+	m, n := a.Dim(0), a.Dim(1)
+	k := min(m, n)
+	work := a.Clone()
+	r := work.Rows()
+
+	// reflectors[j] is the Householder vector v of H_j = I - 2·v·vᵀ/(vᵀv),
+	// acting on rows j..m-1; nil when column j needed no reflection.
+	reflectors := make([][]float64, k)
+	for j := 0; j < k; j++ {
+		var norm float64
+		for i := j; i < m; i++ {
+			norm = math.Hypot(norm, r[i][j])
+		}
+		if norm == 0 {
+			continue
+		}
+		// Reflect onto -sign(r[j][j])·‖x‖·e₁ so that v[0] does not cancel.
+		alpha := -math.Copysign(norm, r[j][j])
+		v := make([]float64, m-j)
+		for i := j; i < m; i++ {
+			v[i-j] = r[i][j]
+		}
+		v[0] -= alpha
+		applyReflector(v, r[j:], j)
+		for i := j + 1; i < m; i++ {
+			r[i][j] = 0
+		}
+		reflectors[j] = v
+	}
+
+	// Q = H_0·H_1·…·H_{k-1} applied to the first k columns of the identity.
+	q := NewTensor(m, k)
+	qr := q.Rows()
+	for i := 0; i < k; i++ {
+		qr[i][i] = 1
+	}
+	for j := k - 1; j >= 0; j-- {
+		if reflectors[j] != nil {
+			applyReflector(reflectors[j], qr[j:], 0)
+		}
+	}
+
+	rk, err := work.Slice(0, 0, k)
+	if err != nil {
+		return nil, err
+	}
+	return &QR{q: q, r: rk.Clone()}, nil
+}
+
+// applyReflector applies H = I - 2·v·vᵀ/(vᵀv) to columns from..end of the
+// rows, which must be as many as len(v).
+func applyReflector(v []float64, rows [][]float64, from int) {
+	var vv float64
+	for _, x := range v {
+		vv += x * x
+	}
+	if vv == 0 {
+		return
+	}
+	for c := from; c < len(rows[0]); c++ {
+		var s float64
+		for i, x := range v {
+			s += x * rows[i][c]
+		}
+		s *= 2 / vv
+		for i, x := range v {
+			rows[i][c] -= s * x
+		}
+	}
+}
+
+// Q returns the m×k factor with orthonormal columns.
+func (f *QR) Q() *Tensor { return f.q.Clone() }
+
+// R returns the k×n upper-triangular factor.
+func (f *QR) R() *Tensor { return f.r.Clone() }
+
+// Solve returns the least-squares solution x minimizing ‖A·x - b‖ for an
+// m×n matrix with m ≥ n and full column rank, where b is a vector of
+// length m or an m×k matrix. Rank-deficient problems need Pinv.
+func (f *QR) Solve(b *Tensor) (*Tensor, error) {
+	m, n := f.q.Dim(0), f.r.Dim(1)
+	if m < n {
+		return nil, fmt.Errorf("QR.Solve: system is underdetermined (%d×%d), use Pinv", m, n)
+	}
+	bm, vector, err := rhs("QR.Solve", b, m)
+	if err != nil {
+		return nil, err
+	}
+	// x = R⁻¹·Qᵀ·b
+	x := NewTensor(n, bm.Dim(1))
+	if err := gemmNative(true, false, 1, f.q, bm, 0, x); err != nil {
+		return nil, err
+	}
+	w, bx := f.r.Rows(), x.Rows()
+	for i := n - 1; i >= 0; i-- {
+		if w[i][i] == 0 {
+			return nil, errors.New("QR.Solve: matrix is rank deficient, use Pinv")
+		}
+		for k := i + 1; k < n; k++ {
+			for j := range bx[i] {
+				bx[i][j] -= w[i][k] * bx[k][j]
+			}
+		}
+		for j := range bx[i] {
+			bx[i][j] /= w[i][i]
+		}
+	}
+	return solution(x, vector), nil
+}
+
+//? --------------------
+//? Singular Value Decomposition
+//? --------------------
+
+// SVD is the thin singular value decomposition A = U·diag(S)·Vᵀ of an m×n
+// matrix: with k = min(m, n), U is m×k and V is n×k with orthonormal
+// columns, and S holds k singular values in decreasing order.
+type SVD struct {
+	u, v   *Tensor
+	values []float64
+}
+
+// SVD decomposes a matrix with one-sided Jacobi rotations, which find even
+// tiny singular values to high relative accuracy.
+func (ng *NumGo) SVD(a *Tensor) (*SVD, error) {
+	if a == nil || a.Dims() != 2 {
+		return nil, errors.New("SVD: need a matrix")
+	}
+	// Work on the tall orientation; A = U·S·Vᵀ turns into Aᵀ = V·S·Uᵀ.
+	wide := a.Dim(0) < a.Dim(1)
+	src := a
+	if wide {
+		src = a.T()
+	}
+	m, n := src.Dim(0), src.Dim(1)
+	u := src.Clone().Rows()
+	v := Eye(n).Rows()
+
+	// Rotate pairs of columns of U until all of them are orthogonal; V
+	// accumulates the rotations so that A·V = U throughout.
+	converged := n < 2
+	for sweep := 0; sweep < maxSweeps && !converged; sweep++ {
+		converged = true
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				var alpha, beta, gamma float64
+				for i := 0; i < m; i++ {
+					alpha += u[i][p] * u[i][p]
+					beta += u[i][q] * u[i][q]
+					gamma += u[i][p] * u[i][q]
+				}
+				if math.Abs(gamma) <= epsilon*math.Sqrt(alpha*beta) {
+					continue
+				}
+				converged = false
+				c, s := jacobiRotation(alpha, beta, gamma)
+				rotateColumns(u, p, q, c, s)
+				rotateColumns(v, p, q, c, s)
+			}
+		}
+	}
+	if !converged {
+		return nil, errors.New("SVD: did not converge")
+	}
+
+	// The column norms of U are the singular values.
+	values := make([]float64, n)
+	for j := range values {
+		for i := 0; i < m; i++ {
+			values[j] = math.Hypot(values[j], u[i][j])
+		}
+	}
+	order := make([]int, n)
+	for j := range order {
+		order[j] = j
+	}
+	sort.SliceStable(order, func(x, y int) bool { return values[order[x]] > values[order[y]] })
+
+	f := &SVD{u: NewTensor(m, n), v: NewTensor(n, n), values: make([]float64, n)}
+	uCols := make([][]float64, n)
+	for jj, j := range order {
+		f.values[jj] = values[j]
+		col := make([]float64, m)
+		for i := range col {
+			if values[j] > 0 {
+				col[i] = u[i][j] / values[j]
+			}
+		}
+		uCols[jj] = col
+		for i := 0; i < n; i++ {
+			f.v.Set(v[i][j], i, jj)
+		}
+	}
+	completeBasis(uCols, f.values)
+	for jj, col := range uCols {
+		for i, x := range col {
+			f.u.Set(x, i, jj)
+		}
+	}
+
+	if wide {
+		f.u, f.v = f.v, f.u
+	}
+	return f, nil
+}
+
+// jacobiRotation returns the rotation (c, s) that zeroes the inner product
+// gamma of two vectors with squared norms alpha and beta.
+func jacobiRotation(alpha, beta, gamma float64) (float64, float64) {
+	zeta := (beta - alpha) / (2 * gamma)
+	t := math.Copysign(1, zeta) / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+	c := 1 / math.Sqrt(1+t*t)
+	return c, c * t
+}
+
+// rotateColumns replaces columns p and q of m by c·p - s·q and s·p + c·q.
+func rotateColumns(m [][]float64, p, q int, c, s float64) {
+	for _, row := range m {
+		x, y := row[p], row[q]
+		row[p] = c*x - s*y
+		row[q] = s*x + c*y
+	}
+}
+
+// completeBasis replaces the columns whose singular value is exactly zero,
+// which Jacobi leaves as zero vectors, with unit vectors orthogonal to all
+// other columns, so U keeps orthonormal columns for rank-deficient input.
+func completeBasis(cols [][]float64, values []float64) {
+	for j, col := range cols {
+		if values[j] > 0 {
+			continue
+		}
+		// Some standard basis vector keeps at least half of its length
+		// after removing the components along the other columns.
+		for e := range col {
+			clear(col)
+			col[e] = 1
+			for pass := 0; pass < 2; pass++ {
+				for k, other := range cols {
+					if k == j || (values[k] == 0 && k > j) {
+						continue
+					}
+					var d float64
+					for i := range col {
+						d += col[i] * other[i]
+					}
+					for i := range col {
+						col[i] -= d * other[i]
+					}
+				}
+			}
+			var norm float64
+			for _, x := range col {
+				norm = math.Hypot(norm, x)
+			}
+			if norm > 0.5 {
+				for i := range col {
+					col[i] /= norm
+				}
+				break
+			}
+		}
+	}
+}
+
+// U returns the m×k matrix of left singular vectors.
+func (f *SVD) U() *Tensor { return f.u.Clone() }
+
+// V returns the n×k matrix of right singular vectors.
+func (f *SVD) V() *Tensor { return f.v.Clone() }
+
+// Values returns the singular values in decreasing order.
+func (f *SVD) Values() []float64 { return append([]float64(nil), f.values...) }
+
+// Rank returns the number of singular values above the default tolerance
+// max(m, n)·ε·σ₁ used by Pinv.
+func (f *SVD) Rank() int {
+	tol := f.tolerance()
+	rank := 0
+	for _, s := range f.values {
+		if s > tol {
+			rank++
+		}
+	}
+	return rank
+}
+
+// tolerance is the threshold below which singular values count as zero.
+func (f *SVD) tolerance() float64 {
+	if len(f.values) == 0 {
+		return 0
+	}
+	return float64(max(f.u.Dim(0), f.v.Dim(0))) * epsilon * f.values[0]
+}
+
+//? --------------------
+//? Symmetric Eigendecomposition
+//? --------------------
+
+// Eigen is the eigendecomposition A = V·diag(values)·Vᵀ of a symmetric
+// matrix: V is orthogonal and its columns are the eigenvectors.
+type Eigen struct {
+	values  []float64
+	vectors *Tensor
+}
+
+// EigenSym diagonalizes a symmetric matrix with cyclic Jacobi rotations.
+// Eigenvalues come in increasing order, as from np.linalg.eigh.
+func (ng *NumGo) EigenSym(a *Tensor) (*Eigen, error) {
+	n, err := square("EigenSym", a)
+	if err != nil {
+		return nil, err
+	}
+	w := a.Clone().Rows()
+	var scale float64
+	for i := range w {
+		for j := range w[i] {
+			scale = math.Max(scale, math.Abs(w[i][j]))
+		}
+	}
+	for i := range w {
+		for j := i + 1; j < n; j++ {
+			if math.Abs(w[i][j]-w[j][i]) > 1e-10*scale {
+				return nil, errors.New("EigenSym: matrix is not symmetric")
+			}
+			// Average away rounding-level asymmetry.
+			w[i][j] = (w[i][j] + w[j][i]) / 2
+			w[j][i] = w[i][j]
+		}
+	}
+
+	vectors := Eye(n)
+	v := vectors.Rows()
+	converged := false
+	for sweep := 0; sweep < maxSweeps && !converged; sweep++ {
+		var off, total float64
+		for i := range w {
+			for j := range w[i] {
+				total += w[i][j] * w[i][j]
+				if i != j {
+					off += w[i][j] * w[i][j]
+				}
+			}
+		}
+		if off <= epsilon*epsilon*total {
+			converged = true
+			break
+		}
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				if w[p][q] == 0 {
+					continue
+				}
+				// Rotate in the (p, q) plane so that w[p][q] becomes zero.
+				c, s := jacobiRotation(w[p][p], w[q][q], w[p][q])
+				rotateColumns(w, p, q, c, s)
+				for k := range w[p] {
+					x, y := w[p][k], w[q][k]
+					w[p][k] = c*x - s*y
+					w[q][k] = s*x + c*y
+				}
+				rotateColumns(v, p, q, c, s)
+			}
+		}
+	}
+	if !converged {
+		return nil, errors.New("EigenSym: did not converge")
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(x, y int) bool { return w[order[x]][order[x]] < w[order[y]][order[y]] })
+	f := &Eigen{values: make([]float64, n), vectors: NewTensor(n, n)}
+	for jj, j := range order {
+		f.values[jj] = w[j][j]
+		for i := 0; i < n; i++ {
+			f.vectors.Set(v[i][j], i, jj)
+		}
+	}
+	return f, nil
+}
+
+// Values returns the eigenvalues in increasing order.
+func (f *Eigen) Values() []float64 { return append([]float64(nil), f.values...) }
+
+// Vectors returns the orthogonal matrix whose column j is the eigenvector
+// of Values()[j].
+func (f *Eigen) Vectors() *Tensor { return f.vectors.Clone() }
+
+//? --------------------
+//? Derived Operations
+//? --------------------
+
+// Det returns the determinant of a square matrix via LU.
+func (ng *NumGo) Det(a *Tensor) (float64, error) {
+	f, err := ng.LU(a)
+	if err != nil {
+		return 0, fmt.Errorf("Det: %w", err)
+	}
+	return f.Det(), nil
+}
+
+// Inverse returns the inverse of a square matrix via LU. It fails if the
+// matrix is singular; Pinv handles that case.
+func (ng *NumGo) Inverse(a *Tensor) (*Tensor, error) {
+	f, err := ng.LU(a)
+	if err != nil {
+		return nil, fmt.Errorf("Inverse: %w", err)
+	}
+	inv, err := f.Solve(Eye(a.Dim(0)))
+	if err != nil {
+		return nil, fmt.Errorf("Inverse: %w", err)
+	}
+	return inv, nil
+}
+
+// Solve returns x with a·x = b for a square, non-singular a, where b is a
+// vector or a matrix of right-hand sides. Over- and underdetermined systems
+// go through QR.Solve or Pinv.
+func (ng *NumGo) Solve(a, b *Tensor) (*Tensor, error) {
+	f, err := ng.LU(a)
+	if err != nil {
+		return nil, fmt.Errorf("Solve: %w", err)
+	}
+	x, err := f.Solve(b)
+	if err != nil {
+		return nil, fmt.Errorf("Solve: %w", err)
+	}
+	return x, nil
+}
+
+// Pinv returns the Moore–Penrose pseudo-inverse V·diag(S⁺)·Uᵀ of an m×n
+// matrix, treating singular values below max(m, n)·ε·σ₁ as zero. Pinv(a)·b
+// is the minimum-norm least-squares solution of a·x = b.
+func (ng *NumGo) Pinv(a *Tensor) (*Tensor, error) {
+	f, err := ng.SVD(a)
+	if err != nil {
+		return nil, fmt.Errorf("Pinv: %w", err)
+	}
+	tol := f.tolerance()
+	// Scale column j of U by 1/σⱼ, then multiply by V from the left.
+	scaled := f.U()
+	rows := scaled.Rows()
+	for j, s := range f.values {
+		inv := 0.0
+		if s > tol {
+			inv = 1 / s
+		}
+		for _, row := range rows {
+			row[j] *= inv
+		}
+	}
+	out := NewTensor(a.Dim(1), a.Dim(0))
+	if err := ng.backend().Gemm(false, true, 1, f.v, scaled, 0, out); err != nil {
+		return nil, fmt.Errorf("Pinv: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,375 @@
+package mathx
+
+import (
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+// maxDiff returns the largest absolute elementwise difference of a and b.
+func maxDiff(t *testing.T, a, b *Tensor) float64 {
+	t.Helper()
+	if !SameShape(a, b) {
+		t.Fatalf("shapes %v and %v differ", a.Shape(), b.Shape())
+	}
+	x, y := a.Data(), b.Data()
+	var d float64
+	for i := range x {
+		d = math.Max(d, math.Abs(x[i]-y[i]))
+	}
+	return d
+}
+
+// product returns op(a)·op(b).
+func product(t *testing.T, transA, transB bool, a, b *Tensor) *Tensor {
+	t.Helper()
+	m, n := a.Dim(0), b.Dim(1)
+	if transA {
+		m = a.Dim(1)
+	}
+	if transB {
+		n = b.Dim(0)
+	}
+	c := NewTensor(m, n)
+	if err := gemmNative(transA, transB, 1, a, b, 0, c); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// checkOrthonormal verifies that q has orthonormal columns.
+func checkOrthonormal(t *testing.T, name string, q *Tensor) {
+	t.Helper()
+	if d := maxDiff(t, product(t, true, false, q, q), Eye(q.Dim(1))); d > 1e-12 {
+		t.Errorf("%sᵀ·%s differs from I by %g", name, name, d)
+	}
+}
+
+// scaleColumns returns a·diag(s).
+func scaleColumns(a *Tensor, s []float64) *Tensor {
+	out := a.Clone()
+	for _, row := range out.Rows() {
+		for j := range row {
+			row[j] *= s[j]
+		}
+	}
+	return out
+}
+
+func TestLU(t *testing.T) {
+	ng := &NumGo{}
+	rng := rand.New(rand.NewPCG(1, 2))
+	a := randomTensor(rng, 6, 6)
+
+	f, err := ng.LU(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pa, err := a.Take(f.Pivot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := maxDiff(t, product(t, false, false, f.L(), f.U()), pa); d > 1e-12 {
+		t.Errorf("L·U differs from P·A by %g", d)
+	}
+	for i, row := range f.L().Rows() {
+		for j, v := range row {
+			if j > i && v != 0 || j == i && v != 1 || j < i && math.Abs(v) > 1 {
+				t.Fatalf("L[%d][%d] = %v breaks unit lower triangular form with partial pivoting", i, j, v)
+			}
+		}
+	}
+
+	b := randomTensor(rng, 6, 3)
+	x, err := f.Solve(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := maxDiff(t, product(t, false, false, a, x), b); d > 1e-10 {
+		t.Errorf("A·x differs from b by %g", d)
+	}
+}
+
+func TestDet(t *testing.T) {
+	ng := &NumGo{}
+	tests := []struct {
+		m    [][]float64
+		want float64
+	}{
+		{[][]float64{{1, 2}, {3, 4}}, -2},
+		{[][]float64{{0, 1}, {1, 0}}, -1},
+		{[][]float64{{2, 0, 0}, {0, 3, 0}, {0, 0, 4}}, 24},
+		{[][]float64{{1, 2}, {2, 4}}, 0},
+	}
+	for _, tt := range tests {
+		a, _ := FromMatrix(tt.m)
+		got, err := ng.Det(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !almostEqual(got, tt.want, 1e-12) {
+			t.Errorf("Det(%v) = %v; want %v", tt.m, got, tt.want)
+		}
+	}
+	if _, err := ng.Det(NewTensor(2, 3)); err == nil {
+		t.Error("Det of a 2×3 matrix should fail")
+	}
+}
+
+func TestInverseAndSolve(t *testing.T) {
+	ng := &NumGo{}
+	rng := rand.New(rand.NewPCG(3, 4))
+	a := randomTensor(rng, 5, 5)
+
+	inv, err := ng.Inverse(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := maxDiff(t, product(t, false, false, a, inv), Eye(5)); d > 1e-10 {
+		t.Errorf("A·A⁻¹ differs from I by %g", d)
+	}
+
+	// Vector right-hand sides give vector solutions.
+	b, _ := FromSlice([]float64{1, 2, 3, 4, 5}, 5)
+	x, err := ng.Solve(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Dims() != 1 || x.Dim(0) != 5 {
+		t.Fatalf("Solve() shape = %v; want [5]", x.Shape())
+	}
+	col, _ := x.Reshape(5, 1)
+	bc, _ := b.Reshape(5, 1)
+	if d := maxDiff(t, product(t, false, false, a, col), bc); d > 1e-10 {
+		t.Errorf("A·x differs from b by %g", d)
+	}
+
+	singular, _ := FromMatrix([][]float64{{1, 2}, {2, 4}})
+	if _, err := ng.Inverse(singular); err == nil || !strings.Contains(err.Error(), "singular") {
+		t.Errorf("Inverse(singular) error = %v; want singular", err)
+	}
+	if _, err := ng.Solve(a, NewTensor(4)); err == nil {
+		t.Error("Solve with a mismatched right-hand side should fail")
+	}
+}
+
+func TestCholesky(t *testing.T) {
+	ng := &NumGo{}
+	rng := rand.New(rand.NewPCG(5, 6))
+	g := randomTensor(rng, 5, 5)
+	// Gᵀ·G + I is symmetric positive definite.
+	a := product(t, true, false, g, g)
+	for i := 0; i < 5; i++ {
+		a.Set(a.At(i, i)+1, i, i)
+	}
+
+	f, err := ng.Cholesky(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := f.L()
+	if d := maxDiff(t, product(t, false, true, l, l), a); d > 1e-12 {
+		t.Errorf("L·Lᵀ differs from A by %g", d)
+	}
+	det, _ := ng.Det(a)
+	if !almostEqual(f.Det(), det, 1e-9*math.Abs(det)) {
+		t.Errorf("Cholesky Det() = %v; want %v", f.Det(), det)
+	}
+	b := randomTensor(rng, 5, 2)
+	x, err := f.Solve(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := maxDiff(t, product(t, false, false, a, x), b); d > 1e-10 {
+		t.Errorf("A·x differs from b by %g", d)
+	}
+
+	indefinite, _ := FromMatrix([][]float64{{1, 2}, {2, 1}})
+	if _, err := ng.Cholesky(indefinite); err == nil {
+		t.Error("Cholesky of an indefinite matrix should fail")
+	}
+}
+
+func TestQR(t *testing.T) {
+	ng := &NumGo{}
+	rng := rand.New(rand.NewPCG(7, 8))
+	for _, shape := range [][2]int{{6, 4}, {4, 6}, {5, 5}, {1, 3}} {
+		a := randomTensor(rng, shape[0], shape[1])
+		f, err := ng.QR(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q, r := f.Q(), f.R()
+		k := min(shape[0], shape[1])
+		if q.Dim(0) != shape[0] || q.Dim(1) != k || r.Dim(0) != k || r.Dim(1) != shape[1] {
+			t.Fatalf("QR of %v: Q %v, R %v", shape, q.Shape(), r.Shape())
+		}
+		checkOrthonormal(t, "Q", q)
+		for i, row := range r.Rows() {
+			for j := 0; j < i; j++ {
+				if row[j] != 0 {
+					t.Fatalf("R[%d][%d] = %v below the diagonal", i, j, row[j])
+				}
+			}
+		}
+		if d := maxDiff(t, product(t, false, false, q, r), a); d > 1e-12 {
+			t.Errorf("QR of %v: Q·R differs from A by %g", shape, d)
+		}
+	}
+}
+
+func TestQRSolveLeastSquares(t *testing.T) {
+	ng := &NumGo{}
+	// Fit y = 1 + 2x through points that lie exactly on the line, and
+	// through points off it, whose least-squares fit is known.
+	a, _ := FromMatrix([][]float64{{1, 0}, {1, 1}, {1, 2}, {1, 3}})
+	f, err := ng.QR(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		y, want []float64
+	}{
+		{[]float64{1, 3, 5, 7}, []float64{1, 2}},
+		{[]float64{0, 1, 1, 3}, []float64{-0.1, 0.9}},
+	}
+	for _, tt := range tests {
+		y, _ := FromSlice(tt.y, 4)
+		x, err := f.Solve(y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, w := range tt.want {
+			if !almostEqual(x.At(i), w, 1e-12) {
+				t.Errorf("QR.Solve(%v) = %v; want %v", tt.y, x.Data(), tt.want)
+				break
+			}
+		}
+	}
+
+	wide, _ := ng.QR(NewTensor(2, 3))
+	if _, err := wide.Solve(NewTensor(2)); err == nil {
+		t.Error("QR.Solve of an underdetermined system should fail")
+	}
+}
+
+func TestSVD(t *testing.T) {
+	ng := &NumGo{}
+	rng := rand.New(rand.NewPCG(9, 10))
+	// Rank 2 matrices exercise the completion of U for zero singular values.
+	rank2 := product(t, false, false, randomTensor(rng, 5, 2), randomTensor(rng, 2, 4))
+	inputs := []*Tensor{
+		randomTensor(rng, 7, 4),
+		randomTensor(rng, 3, 6),
+		randomTensor(rng, 5, 5),
+		rank2,
+		rank2.T(),
+		NewTensor(3, 2),
+	}
+	for _, a := range inputs {
+		f, err := ng.SVD(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, s, v := f.U(), f.Values(), f.V()
+		k := min(a.Dim(0), a.Dim(1))
+		if u.Dim(0) != a.Dim(0) || u.Dim(1) != k || v.Dim(0) != a.Dim(1) || v.Dim(1) != k || len(s) != k {
+			t.Fatalf("SVD of %v: U %v, V %v, %d values", a.Shape(), u.Shape(), v.Shape(), len(s))
+		}
+		for i := 1; i < k; i++ {
+			if s[i] > s[i-1] || s[i] < 0 {
+				t.Fatalf("SVD of %v: singular values %v not decreasing and non-negative", a.Shape(), s)
+			}
+		}
+		checkOrthonormal(t, "U", u)
+		checkOrthonormal(t, "V", v)
+		if d := maxDiff(t, product(t, false, true, scaleColumns(u, s), v), a.Clone()); d > 1e-12 {
+			t.Errorf("SVD of %v: U·S·Vᵀ differs from A by %g", a.Shape(), d)
+		}
+	}
+
+	f, _ := ng.SVD(rank2)
+	if f.Rank() != 2 {
+		t.Errorf("Rank() = %d; want 2", f.Rank())
+	}
+	d, _ := FromMatrix([][]float64{{0, 3, 0}, {-4, 0, 0}})
+	f, _ = ng.SVD(d)
+	if s := f.Values(); !almostEqual(s[0], 4, 1e-12) || !almostEqual(s[1], 3, 1e-12) {
+		t.Errorf("SVD().Values() = %v; want [4 3]", s)
+	}
+}
+
+func TestEigenSym(t *testing.T) {
+	ng := &NumGo{}
+	a, _ := FromMatrix([][]float64{{2, 1}, {1, 2}})
+	f, err := ng.EigenSym(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Values(); !almostEqual(got[0], 1, 1e-12) || !almostEqual(got[1], 3, 1e-12) {
+		t.Errorf("EigenSym().Values() = %v; want [1 3]", got)
+	}
+
+	rng := rand.New(rand.NewPCG(11, 12))
+	g := randomTensor(rng, 6, 6)
+	sym, _ := ng.Add(g, g.T())
+	f, err = ng.EigenSym(sym)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vals, vecs := f.Values(), f.Vectors()
+	for i := 1; i < len(vals); i++ {
+		if vals[i] < vals[i-1] {
+			t.Fatalf("eigenvalues %v not increasing", vals)
+		}
+	}
+	checkOrthonormal(t, "V", vecs)
+	if d := maxDiff(t, product(t, false, false, sym, vecs), scaleColumns(vecs, vals)); d > 1e-12 {
+		t.Errorf("A·V differs from V·Λ by %g", d)
+	}
+
+	if _, err := ng.EigenSym(g); err == nil {
+		t.Error("EigenSym of a non-symmetric matrix should fail")
+	}
+}
+
+func TestPinv(t *testing.T) {
+	ng := &NumGo{}
+	rng := rand.New(rand.NewPCG(13, 14))
+
+	// For an invertible matrix the pseudo-inverse is the inverse.
+	a := randomTensor(rng, 4, 4)
+	pinv, err := ng.Pinv(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv, _ := ng.Inverse(a)
+	if d := maxDiff(t, pinv, inv); d > 1e-10 {
+		t.Errorf("Pinv differs from Inverse by %g", d)
+	}
+
+	// The Moore–Penrose conditions hold for rank-deficient matrices.
+	a = product(t, false, false, randomTensor(rng, 6, 2), randomTensor(rng, 2, 4))
+	p, err := ng.Pinv(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Dim(0) != 4 || p.Dim(1) != 6 {
+		t.Fatalf("Pinv() shape = %v; want [4 6]", p.Shape())
+	}
+	ap := product(t, false, false, a, p)
+	pa := product(t, false, false, p, a)
+	if d := maxDiff(t, product(t, false, false, ap, a), a); d > 1e-10 {
+		t.Errorf("A·A⁺·A differs from A by %g", d)
+	}
+	if d := maxDiff(t, product(t, false, false, pa, p), p); d > 1e-10 {
+		t.Errorf("A⁺·A·A⁺ differs from A⁺ by %g", d)
+	}
+	if d := maxDiff(t, ap, ap.T().Clone()); d > 1e-10 {
+		t.Errorf("A·A⁺ is not symmetric (%g)", d)
+	}
+	if d := maxDiff(t, pa, pa.T().Clone()); d > 1e-10 {
+		t.Errorf("A⁺·A is not symmetric (%g)", d)
+	}
+}
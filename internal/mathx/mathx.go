@@ -2,11 +2,16 @@ package mathx
 
 import (
 	"errors"
+	"fmt"
 	"math"
 )
 
-// NumGo provides basic numerical and linear algebra operations.
-type NumGo struct{}
+// NumGo provides basic numerical and linear algebra operations. Products,
+// transposes and vector operations run on Backend, or on DefaultBackend
+// when it is nil, so the zero value is ready to use.
+type NumGo struct {
+	Backend Backend
+}
 
 //? --------------------
 //? Scalar Operations
@@ -30,11 +35,7 @@ func (ng *NumGo) DotVectors(v1, v2 []float64) (float64, error) {
 	if len(v1) != len(v2) {
 		return 0, errors.New("DotVectors: vector length mismatch")
 	}
-	var result float64
-	for i := range v1 {
-		result += v1[i] * v2[i]
-	}
-	return result, nil
+	return ng.backend().Dot(v1, v2), nil
 }
 
 // AddVectors returns the element-wise sum of two vectors.
@@ -43,9 +44,8 @@ func (ng *NumGo) AddVectors(v1, v2 []float64) ([]float64, error) {
 		return nil, errors.New("AddVectors: vector length mismatch")
 	}
 	result := make([]float64, len(v1))
-	for i := range v1 {
-		result[i] = v1[i] + v2[i]
-	}
+	copy(result, v1)
+	ng.backend().Axpy(1, v2, result)
 	return result, nil
 }
 
@@ -55,28 +55,22 @@ func (ng *NumGo) SubVectors(v1, v2 []float64) ([]float64, error) {
 		return nil, errors.New("SubVectors: vector length mismatch")
 	}
 	result := make([]float64, len(v1))
-	for i := range v1 {
-		result[i] = v1[i] - v2[i]
-	}
+	copy(result, v1)
+	ng.backend().Axpy(-1, v2, result)
 	return result, nil
 }
 
 // ScaleVector scales all elements of a vector by a constant factor.
 func (ng *NumGo) ScaleVector(v []float64, scalar float64) []float64 {
 	result := make([]float64, len(v))
-	for i := range v {
-		result[i] = v[i] * scalar
-	}
+	copy(result, v)
+	ng.backend().Scal(scalar, result)
 	return result
 }
 
 // Norm returns the Euclidean (L2) norm of a vector.
 func (ng *NumGo) Norm(v []float64) float64 {
-	var sum float64
-	for _, x := range v {
-		sum += x * x
-	}
-	return math.Sqrt(sum)
+	return ng.backend().Nrm2(v)
 }
 
 // Normalize returns a normalized version of the vector (unit vector).
@@ -85,11 +79,7 @@ func (ng *NumGo) Normalize(v []float64) []float64 {
 	if norm == 0 {
 		return make([]float64, len(v))
 	}
-	result := make([]float64, len(v))
-	for i := range v {
-		result[i] = v[i] / norm
-	}
-	return result
+	return ng.ScaleVector(v, 1/norm)
 }
 
 // MaxVector returns elementwise maximum between two float64 slices (like np.maximum).
@@ -114,42 +104,41 @@ func MaxVector(a, b []float64) ([]float64, error) {
 //? --------------------
 
 // MatrixMul performs matrix multiplication A (m×n) * B (n×p) = C (m×p).
+// It copies its operands into Tensors and runs the backend's Gemm; use
+// MatMul or Gemm directly to avoid the conversions.
 func (ng *NumGo) DotMatrix(A, B [][]float64) ([][]float64, error) {
 	if len(A) == 0 || len(B) == 0 {
 		return nil, errors.New("MatrixMul: empty matrix")
 	}
-	n, p := len(B), len(B[0])
-	if len(A[0]) != n {
+	a, err := FromMatrix(A)
+	if err != nil {
+		return nil, fmt.Errorf("MatrixMul: %w", err)
+	}
+	b, err := FromMatrix(B)
+	if err != nil {
+		return nil, fmt.Errorf("MatrixMul: %w", err)
+	}
+	if a.Dim(1) != b.Dim(0) {
 		return nil, errors.New("MatrixMul: incompatible dimensions")
 	}
-
-	m := len(A)
-	C := make([][]float64, m)
-	for i := range C {
-		C[i] = make([]float64, p)
-		for j := 0; j < p; j++ {
-			for k := 0; k < n; k++ {
-				C[i][j] += A[i][k] * B[k][j]
-			}
-		}
+	C := NewTensor(a.Dim(0), b.Dim(1))
+	if err := ng.backend().Gemm(false, false, 1, a, b, 0, C); err != nil {
+		return nil, fmt.Errorf("MatrixMul: %w", err)
 	}
-	return C, nil
+	return C.ToMatrix(), nil
 }
 
-// Transpose returns the transpose of a matrix.
+// Transpose returns the transpose of a rectangular matrix. Tensor.T gives
+// the same result as a view, without copying.
 func (ng *NumGo) Transpose(M [][]float64) [][]float64 {
 	if len(M) == 0 {
 		return [][]float64{}
 	}
-	rows, cols := len(M), len(M[0])
-	T := make([][]float64, cols)
-	for i := range T {
-		T[i] = make([]float64, rows)
-		for j := range M {
-			T[i][j] = M[j][i]
-		}
+	t, err := FromMatrix(M)
+	if err != nil {
+		panic("Transpose: " + err.Error())
 	}
-	return T
+	return ng.backend().Transpose(t).ToMatrix()
 }
 
 // MaxMatrix returns elementwise maximum between two 2D slices (matrices).
@@ -0,0 +1,175 @@
+package mathx
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+//? --------------------
+//? Reductions
+//? --------------------
+
+// Sum returns the sum of t over the given axes. Like every reduction, no
+// axes means all of them and negative axes count from the end, as in NumPy;
+// with keepDims the reduced axes stay in the result with size 1, so it
+// broadcasts back against t.
+func (ng *NumGo) Sum(t *Tensor, keepDims bool, axes ...int) (*Tensor, error) {
+	out, _, err := reduce(t, axes, keepDims, 0, func(acc, x float64) float64 { return acc + x })
+	if err != nil {
+		return nil, fmt.Errorf("Sum: %w", err)
+	}
+	return out, nil
+}
+
+// Mean returns the arithmetic mean of t over the given axes.
+func (ng *NumGo) Mean(t *Tensor, keepDims bool, axes ...int) (*Tensor, error) {
+	out, count, err := reduce(t, axes, keepDims, 0, func(acc, x float64) float64 { return acc + x })
+	if err != nil {
+		return nil, fmt.Errorf("Mean: %w", err)
+	}
+	if count == 0 {
+		return nil, errors.New("Mean: empty reduction")
+	}
+	n := float64(count)
+	out.Apply(func(s float64) float64 { return s / n })
+	return out, nil
+}
+
+// Max returns the maximum of t over the given axes.
+func (ng *NumGo) Max(t *Tensor, keepDims bool, axes ...int) (*Tensor, error) {
+	out, count, err := reduce(t, axes, keepDims, math.Inf(-1), math.Max)
+	if err != nil {
+		return nil, fmt.Errorf("Max: %w", err)
+	}
+	if count == 0 {
+		return nil, errors.New("Max: empty reduction")
+	}
+	return out, nil
+}
+
+// Var returns the population variance (ddof = 0, like np.var) of t over the
+// given axes, computed around the mean in two passes for stability.
+func (ng *NumGo) Var(t *Tensor, keepDims bool, axes ...int) (*Tensor, error) {
+	mean, err := ng.Mean(t, true, axes...)
+	if err != nil {
+		return nil, fmt.Errorf("Var: %w", err)
+	}
+	dev, err := ng.Sub(t, mean)
+	if err != nil {
+		return nil, fmt.Errorf("Var: %w", err)
+	}
+	dev.Apply(func(d float64) float64 { return d * d })
+	return ng.Mean(dev, keepDims, axes...)
+}
+
+// ArgMax returns the index of the largest element along axis for every
+// position of the remaining axes, in row-major order of those axes; for a
+// matrix and axis -1 that is the predicted class of each row. Ties resolve to
+// the first index.
+func (ng *NumGo) ArgMax(t *Tensor, axis int) ([]int, error) {
+	if t == nil {
+		return nil, errors.New("ArgMax: nil tensor")
+	}
+	reduced, err := reducedAxes(t.Dims(), []int{axis})
+	if err != nil {
+		return nil, fmt.Errorf("ArgMax: %w", err)
+	}
+	perm := make([]int, 0, t.Dims())
+	for k, r := range reduced {
+		if r {
+			axis = k
+		} else {
+			perm = append(perm, k)
+		}
+	}
+	perm = append(perm, axis)
+	if t.shape[axis] == 0 {
+		return nil, errors.New("ArgMax: empty axis")
+	}
+
+	// Moving the axis last turns each slice along it into a row.
+	moved, err := t.Permute(perm...)
+	if err != nil {
+		return nil, fmt.Errorf("ArgMax: %w", err)
+	}
+	rows := moved.Rows()
+	out := make([]int, len(rows))
+	for i, row := range rows {
+		for j, v := range row {
+			if v > row[out[i]] {
+				out[i] = j
+			}
+		}
+	}
+	return out, nil
+}
+
+// reduce folds t over axes with f, starting every output element from init.
+// It also returns the number of elements folded into each output.
+func reduce(t *Tensor, axes []int, keepDims bool, init float64, f func(acc, x float64) float64) (*Tensor, int, error) {
+	if t == nil {
+		return nil, 0, errors.New("nil tensor")
+	}
+	reduced, err := reducedAxes(t.Dims(), axes)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	kept := make([]int, t.Dims())
+	var squeezed []int
+	count := 1
+	for k, d := range t.shape {
+		if reduced[k] {
+			kept[k] = 1
+			count *= d
+			continue
+		}
+		kept[k] = d
+		squeezed = append(squeezed, d)
+	}
+
+	out := NewTensor(kept...)
+	out.Fill(init)
+	// Broadcasting the output back over t maps every input element onto
+	// the output element it folds into.
+	target, err := out.BroadcastTo(t.shape...)
+	if err != nil {
+		return nil, 0, err
+	}
+	eachPair(t, target, func(pt, po int) {
+		out.data[po] = f(out.data[po], t.data[pt])
+	})
+
+	if !keepDims {
+		out.shape = squeezed
+		out.strides = rowMajorStrides(squeezed)
+	}
+	return out, count, nil
+}
+
+// reducedAxes validates axes for a tensor with dims dimensions and reports
+// which dimensions they select. No axes selects all of them.
+func reducedAxes(dims int, axes []int) ([]bool, error) {
+	reduced := make([]bool, dims)
+	if len(axes) == 0 {
+		for k := range reduced {
+			reduced[k] = true
+		}
+		return reduced, nil
+	}
+	for _, a := range axes {
+		k := a
+		if k < 0 {
+			k += dims
+		}
+		if k < 0 || k >= dims {
+			return nil, fmt.Errorf("axis %d out of range for a %d-dimensional tensor", a, dims)
+		}
+		if reduced[k] {
+			return nil, fmt.Errorf("axis %d repeated", a)
+		}
+		reduced[k] = true
+	}
+	return reduced, nil
+}
@@ -0,0 +1,88 @@
+package mathx
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestReductions(t *testing.T) {
+	ng := NumGo{}
+	// x[i][j][k] = 12i + 4j + k
+	x := NewTensor(2, 3, 4)
+	for i, n := 0, 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 4; k++ {
+				x.Set(float64(n), i, j, k)
+				n++
+			}
+		}
+	}
+
+	tests := []struct {
+		name      string
+		op        func(*Tensor, bool, ...int) (*Tensor, error)
+		keepDims  bool
+		axes      []int
+		wantShape []int
+		want      []float64
+	}{
+		{"Sum all", ng.Sum, false, nil, nil, []float64{276}},
+		{"Sum axis 0", ng.Sum, false, []int{0}, []int{3, 4}, []float64{12, 14, 16, 18, 20, 22, 24, 26, 28, 30, 32, 34}},
+		{"Sum axes 0,2 keep", ng.Sum, true, []int{0, -1}, []int{1, 3, 1}, []float64{60, 92, 124}},
+		{"Mean axis 1", ng.Mean, false, []int{1}, []int{2, 4}, []float64{4, 5, 6, 7, 16, 17, 18, 19}},
+		{"Max axis -1", ng.Max, false, []int{-1}, []int{2, 3}, []float64{3, 7, 11, 15, 19, 23}},
+		{"Var axis 0", ng.Var, false, []int{0}, []int{3, 4}, []float64{36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36}},
+		{"Var axis 2 keep", ng.Var, true, []int{2}, []int{2, 3, 1}, []float64{1.25, 1.25, 1.25, 1.25, 1.25, 1.25}},
+	}
+	for _, tt := range tests {
+		got, err := tt.op(x, tt.keepDims, tt.axes...)
+		if err != nil {
+			t.Errorf("%s returned error: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.Shape(), tt.wantShape) {
+			t.Errorf("%s shape = %v; want %v", tt.name, got.Shape(), tt.wantShape)
+		}
+		for i, v := range got.Data() {
+			if math.Abs(v-tt.want[i]) > 1e-12 {
+				t.Errorf("%s = %v; want %v", tt.name, got.Data(), tt.want)
+				break
+			}
+		}
+	}
+
+	if _, err := ng.Sum(x, false, 3); err == nil {
+		t.Error("Sum(axis 3) expected error on a 3-D tensor, got nil")
+	}
+	if _, err := ng.Sum(x, false, 1, -2); err == nil {
+		t.Error("Sum(axes 1, -2) expected error on a repeated axis, got nil")
+	}
+	if _, err := ng.Max(NewTensor(0, 2), false, 0); err == nil {
+		t.Error("Max() expected error on an empty reduction, got nil")
+	}
+}
+
+func TestArgMax(t *testing.T) {
+	ng := NumGo{}
+	x, _ := FromMatrix([][]float64{{0.1, 0.7, 0.2}, {0.5, 0.5, 0.0}, {-1, -3, -2}})
+
+	rows, err := ng.ArgMax(x, -1)
+	if err != nil {
+		t.Fatalf("ArgMax() returned error: %v", err)
+	}
+	if want := []int{1, 0, 0}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("ArgMax(axis -1) = %v; want %v", rows, want)
+	}
+	cols, _ := ng.ArgMax(x, 0)
+	if want := []int{1, 0, 0}; !reflect.DeepEqual(cols, want) {
+		t.Errorf("ArgMax(axis 0) = %v; want %v", cols, want)
+	}
+	// A transposed view gives the same answer with the axes swapped.
+	if got, _ := ng.ArgMax(x.T(), 1); !reflect.DeepEqual(got, cols) {
+		t.Errorf("ArgMax(xᵀ, axis 1) = %v; want %v", got, cols)
+	}
+	if _, err := ng.ArgMax(x, 2); err == nil {
+		t.Error("ArgMax(axis 2) expected error on a matrix, got nil")
+	}
+}
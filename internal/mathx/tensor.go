@@ -0,0 +1,435 @@
+package mathx
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Tensor is an N-dimensional array of float64 values stored in one flat
+// slice. Element (i0, i1, …) lives at data[offset + Σ ik·strides[k]], so
+// views produced by Slice, Index, T, Permute and Reshape share storage with
+// the tensor they come from and cost no copy.
+//
+// A tensor whose strides are the row-major strides of its shape is
+// contiguous; Data and Rows alias the storage of contiguous tensors only.
+type Tensor struct {
+	data    []float64
+	shape   []int
+	strides []int
+	offset  int
+}
+
+// NewTensor returns a zero-filled contiguous tensor of the given shape.
+// Like make, it panics if a dimension is negative.
+func NewTensor(shape ...int) *Tensor {
+	size := 1
+	for _, d := range shape {
+		if d < 0 {
+			panic(fmt.Sprintf("mathx: negative dimension %d in shape %v", d, shape))
+		}
+		size *= d
+	}
+	return &Tensor{
+		data:    make([]float64, size),
+		shape:   append([]int(nil), shape...),
+		strides: rowMajorStrides(shape),
+	}
+}
+
+// FromSlice wraps data (without copying) as a contiguous tensor of the given
+// shape. len(data) must equal the product of the dimensions.
+func FromSlice(data []float64, shape ...int) (*Tensor, error) {
+	size := 1
+	for _, d := range shape {
+		if d < 0 {
+			return nil, fmt.Errorf("negative dimension %d in shape %v", d, shape)
+		}
+		size *= d
+	}
+	if len(data) != size {
+		return nil, fmt.Errorf("cannot shape %d values as %v", len(data), shape)
+	}
+	return &Tensor{data: data, shape: append([]int(nil), shape...), strides: rowMajorStrides(shape)}, nil
+}
+
+// FromMatrix copies a rectangular [][]float64 into a new 2-D tensor.
+// Ragged input is rejected.
+func FromMatrix(m [][]float64) (*Tensor, error) {
+	if len(m) == 0 {
+		return NewTensor(0, 0), nil
+	}
+	cols := len(m[0])
+	t := NewTensor(len(m), cols)
+	for i, row := range m {
+		if len(row) != cols {
+			return nil, fmt.Errorf("row %d has %d values, expected %d", i, len(row), cols)
+		}
+		copy(t.data[i*cols:], row)
+	}
+	return t, nil
+}
+
+// rowMajorStrides returns the strides of a contiguous tensor of the given shape.
+func rowMajorStrides(shape []int) []int {
+	strides := make([]int, len(shape))
+	stride := 1
+	for k := len(shape) - 1; k >= 0; k-- {
+		strides[k] = stride
+		stride *= shape[k]
+	}
+	return strides
+}
+
+//? --------------------
+//? Shape & Access
+//? --------------------
+
+// Shape returns a copy of the dimensions.
+func (t *Tensor) Shape() []int { return append([]int(nil), t.shape...) }
+
+// Strides returns a copy of the strides, in elements.
+func (t *Tensor) Strides() []int { return append([]int(nil), t.strides...) }
+
+// Dims returns the number of dimensions.
+func (t *Tensor) Dims() int { return len(t.shape) }
+
+// Dim returns the size of dimension k.
+func (t *Tensor) Dim(k int) int { return t.shape[k] }
+
+// Size returns the number of elements.
+func (t *Tensor) Size() int {
+	size := 1
+	for _, d := range t.shape {
+		size *= d
+	}
+	return size
+}
+
+// SameShape reports whether a and b have identical dimensions.
+func SameShape(a, b *Tensor) bool {
+	if len(a.shape) != len(b.shape) {
+		return false
+	}
+	for k := range a.shape {
+		if a.shape[k] != b.shape[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsContiguous reports whether the elements are laid out in row-major order
+// without gaps, as they are for every tensor not produced by a strided view.
+func (t *Tensor) IsContiguous() bool {
+	stride := 1
+	for k := len(t.shape) - 1; k >= 0; k-- {
+		if t.shape[k] != 1 && t.strides[k] != stride {
+			return false
+		}
+		stride *= t.shape[k]
+	}
+	return true
+}
+
+// At returns the element at the given indices. It panics on an out-of-range
+// index, like indexing a slice.
+func (t *Tensor) At(idx ...int) float64 { return t.data[t.pos(idx)] }
+
+// Set stores v at the given indices.
+func (t *Tensor) Set(v float64, idx ...int) { t.data[t.pos(idx)] = v }
+
+// pos converts indices into a position in data.
+func (t *Tensor) pos(idx []int) int {
+	if len(idx) != len(t.shape) {
+		panic(fmt.Sprintf("mathx: %d indices for a %d-dimensional tensor", len(idx), len(t.shape)))
+	}
+	p := t.offset
+	for k, i := range idx {
+		if i < 0 || i >= t.shape[k] {
+			panic(fmt.Sprintf("mathx: index %d out of range for dimension %d of size %d", i, k, t.shape[k]))
+		}
+		p += i * t.strides[k]
+	}
+	return p
+}
+
+// Data returns the elements in row-major order. For a contiguous tensor the
+// slice aliases its storage; otherwise it is a fresh copy.
+func (t *Tensor) Data() []float64 {
+	if t.IsContiguous() {
+		return t.data[t.offset : t.offset+t.Size()]
+	}
+	return t.Clone().data
+}
+
+// Contiguous returns t itself when it is contiguous and a compact copy otherwise.
+func (t *Tensor) Contiguous() *Tensor {
+	if t.IsContiguous() {
+		return t
+	}
+	return t.Clone()
+}
+
+// Clone returns a contiguous deep copy.
+func (t *Tensor) Clone() *Tensor {
+	out := NewTensor(t.shape...)
+	i := 0
+	t.each(func(p int) {
+		out.data[i] = t.data[p]
+		i++
+	})
+	return out
+}
+
+// each calls fn with the storage position of every element, in row-major order.
+func (t *Tensor) each(fn func(p int)) {
+	size := t.Size()
+	if size == 0 {
+		return
+	}
+	if t.IsContiguous() {
+		for p := t.offset; p < t.offset+size; p++ {
+			fn(p)
+		}
+		return
+	}
+
+	idx := make([]int, len(t.shape))
+	p := t.offset
+	for n := 0; n < size; n++ {
+		fn(p)
+		// Advance the multi-index like an odometer.
+		for k := len(idx) - 1; k >= 0; k-- {
+			idx[k]++
+			p += t.strides[k]
+			if idx[k] < t.shape[k] {
+				break
+			}
+			p -= idx[k] * t.strides[k]
+			idx[k] = 0
+		}
+	}
+}
+
+//? --------------------
+//? Views
+//? --------------------
+
+// Reshape returns a tensor with the same elements and a new shape; one
+// dimension may be -1 and is then inferred. The result is a view when t is
+// contiguous and a copy otherwise.
+func (t *Tensor) Reshape(shape ...int) (*Tensor, error) {
+	shape = append([]int(nil), shape...)
+	infer, known := -1, 1
+	for k, d := range shape {
+		switch {
+		case d == -1 && infer < 0:
+			infer = k
+		case d < 0:
+			return nil, fmt.Errorf("invalid dimension %d in shape %v", d, shape)
+		default:
+			known *= d
+		}
+	}
+	size := t.Size()
+	if infer >= 0 {
+		if known == 0 || size%known != 0 {
+			return nil, fmt.Errorf("cannot reshape %v into %v", t.shape, shape)
+		}
+		shape[infer] = size / known
+		known *= shape[infer]
+	}
+	if known != size {
+		return nil, fmt.Errorf("cannot reshape %v (%d elements) into %v", t.shape, size, shape)
+	}
+
+	src := t.Contiguous()
+	return &Tensor{data: src.data, shape: shape, strides: rowMajorStrides(shape), offset: src.offset}, nil
+}
+
+// Slice returns a view of the elements start ≤ i < end along dimension dim.
+func (t *Tensor) Slice(dim, start, end int) (*Tensor, error) {
+	if dim < 0 || dim >= len(t.shape) {
+		return nil, fmt.Errorf("dimension %d out of range for a %d-dimensional tensor", dim, len(t.shape))
+	}
+	if start < 0 || end > t.shape[dim] || start > end {
+		return nil, fmt.Errorf("slice [%d:%d] out of range for dimension %d of size %d", start, end, dim, t.shape[dim])
+	}
+	view := t.view()
+	view.shape[dim] = end - start
+	view.offset += start * t.strides[dim]
+	return view, nil
+}
+
+// Index returns the view of sub-tensor i along the first dimension, with that
+// dimension removed (a row of a matrix, a matrix of a 3-D tensor, …).
+func (t *Tensor) Index(i int) (*Tensor, error) {
+	if len(t.shape) == 0 {
+		return nil, errors.New("cannot index a scalar tensor")
+	}
+	if i < 0 || i >= t.shape[0] {
+		return nil, fmt.Errorf("index %d out of range for dimension of size %d", i, t.shape[0])
+	}
+	return &Tensor{
+		data:    t.data,
+		shape:   append([]int(nil), t.shape[1:]...),
+		strides: append([]int(nil), t.strides[1:]...),
+		offset:  t.offset + i*t.strides[0],
+	}, nil
+}
+
+// Take returns a contiguous copy of the sub-tensors at the given indices
+// along the first dimension, e.g. to gather a mini-batch of rows.
+func (t *Tensor) Take(indices []int) (*Tensor, error) {
+	if len(t.shape) == 0 {
+		return nil, errors.New("cannot take from a scalar tensor")
+	}
+	shape := t.Shape()
+	shape[0] = len(indices)
+	out := NewTensor(shape...)
+	inner := out.Size()
+	if len(indices) > 0 {
+		inner /= len(indices)
+	}
+	for n, i := range indices {
+		sub, err := t.Index(i)
+		if err != nil {
+			return nil, err
+		}
+		copy(out.data[n*inner:(n+1)*inner], sub.Data())
+	}
+	return out, nil
+}
+
+// T returns the transpose view of t: its axes in reverse order. For a matrix
+// this swaps rows and columns without moving any data.
+func (t *Tensor) T() *Tensor {
+	view := t.view()
+	for i, j := 0, len(view.shape)-1; i < j; i, j = i+1, j-1 {
+		view.shape[i], view.shape[j] = view.shape[j], view.shape[i]
+		view.strides[i], view.strides[j] = view.strides[j], view.strides[i]
+	}
+	return view
+}
+
+// Permute returns a view with the axes reordered: dimension k of the result
+// is dimension axes[k] of t.
+func (t *Tensor) Permute(axes ...int) (*Tensor, error) {
+	if len(axes) != len(t.shape) {
+		return nil, fmt.Errorf("permutation %v does not match %d dimensions", axes, len(t.shape))
+	}
+	seen := make([]bool, len(axes))
+	view := t.view()
+	for k, a := range axes {
+		if a < 0 || a >= len(axes) || seen[a] {
+			return nil, fmt.Errorf("invalid permutation %v", axes)
+		}
+		seen[a] = true
+		view.shape[k], view.strides[k] = t.shape[a], t.strides[a]
+	}
+	return view, nil
+}
+
+// view returns a tensor sharing t's storage with its own shape and strides.
+func (t *Tensor) view() *Tensor {
+	return &Tensor{data: t.data, shape: t.Shape(), strides: t.Strides(), offset: t.offset}
+}
+
+//? --------------------
+//? Conversion
+//? --------------------
+
+// Rows splits the tensor along its last axis: a matrix yields its rows, an
+// (N, T, C) tensor N·T vectors of length C. For a contiguous tensor the rows
+// alias its storage, so writing to them writes to the tensor; otherwise they
+// are copies.
+func (t *Tensor) Rows() [][]float64 {
+	src := t.Contiguous()
+	cols, rows := 1, 1
+	if len(src.shape) > 0 {
+		cols = src.shape[len(src.shape)-1]
+		for _, d := range src.shape[:len(src.shape)-1] {
+			rows *= d
+		}
+	}
+	out := make([][]float64, rows)
+	for i := range out {
+		start := src.offset + i*cols
+		out[i] = src.data[start : start+cols : start+cols]
+	}
+	return out
+}
+
+// ToMatrix copies the tensor into a new [][]float64, split along the last
+// axis like Rows.
+func (t *Tensor) ToMatrix() [][]float64 {
+	rows := t.Rows()
+	out := make([][]float64, len(rows))
+	for i, row := range rows {
+		out[i] = append([]float64(nil), row...)
+	}
+	return out
+}
+
+//? --------------------
+//? Elementwise
+//? --------------------
+
+// Fill sets every element to v.
+func (t *Tensor) Fill(v float64) {
+	t.each(func(p int) { t.data[p] = v })
+}
+
+// CopyFrom copies the elements of src, which must have the same shape, into t.
+func (t *Tensor) CopyFrom(src *Tensor) error {
+	if !SameShape(t, src) {
+		return fmt.Errorf("cannot copy shape %v into %v", src.shape, t.shape)
+	}
+	values := src.Data()
+	i := 0
+	t.each(func(p int) {
+		t.data[p] = values[i]
+		i++
+	})
+	return nil
+}
+
+// Map returns a new contiguous tensor with f applied to every element.
+func (t *Tensor) Map(f func(float64) float64) *Tensor {
+	out := NewTensor(t.shape...)
+	i := 0
+	t.each(func(p int) {
+		out.data[i] = f(t.data[p])
+		i++
+	})
+	return out
+}
+
+// Apply replaces every element x with f(x) in place.
+func (t *Tensor) Apply(f func(float64) float64) {
+	t.each(func(p int) { t.data[p] = f(t.data[p]) })
+}
+
+//? --------------------
+//? Matrix Products
+//? --------------------
+
+// MatMul returns the matrix product of a (m×k) and b (k×n). Either operand
+// may be a strided view such as x.T().
+func MatMul(a, b *Tensor) (*Tensor, error) {
+	if a.Dims() != 2 || b.Dims() != 2 {
+		return nil, fmt.Errorf("MatMul needs 2-D tensors, got shapes %v and %v", a.shape, b.shape)
+	}
+	out := NewTensor(a.shape[0], b.shape[1])
+	if err := MatMulInto(out, a, b); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MatMulInto writes a·b into dst, overwriting it, so repeated products can
+// reuse one buffer. dst must be a contiguous m×n tensor that does not share
+// storage with a or b. It is Gemm with alpha 1 and beta 0.
+func MatMulInto(dst, a, b *Tensor) error {
+	return Gemm(false, false, 1, a, b, 0, dst)
+}
@@ -0,0 +1,122 @@
+package mathx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFromMatrixRoundTrip(t *testing.T) {
+	m := [][]float64{{1, 2, 3}, {4, 5, 6}}
+	x, err := FromMatrix(m)
+	if err != nil {
+		t.Fatalf("FromMatrix() returned error: %v", err)
+	}
+	if !reflect.DeepEqual(x.Shape(), []int{2, 3}) || !reflect.DeepEqual(x.Strides(), []int{3, 1}) {
+		t.Errorf("shape %v strides %v; want [2 3] [3 1]", x.Shape(), x.Strides())
+	}
+	if !reflect.DeepEqual(x.ToMatrix(), m) {
+		t.Errorf("ToMatrix() = %v; want %v", x.ToMatrix(), m)
+	}
+
+	if _, err := FromMatrix([][]float64{{1, 2}, {3}}); err == nil {
+		t.Error("FromMatrix() expected error on ragged input, got nil")
+	}
+	if _, err := FromSlice([]float64{1, 2, 3}, 2, 2); err == nil {
+		t.Error("FromSlice() expected error on size mismatch, got nil")
+	}
+}
+
+func TestViewsShareStorage(t *testing.T) {
+	x, _ := FromSlice([]float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, 3, 4)
+
+	cols, err := x.Slice(1, 1, 3)
+	if err != nil {
+		t.Fatalf("Slice() returned error: %v", err)
+	}
+	if cols.IsContiguous() {
+		t.Error("column slice reported as contiguous")
+	}
+	if want := [][]float64{{1, 2}, {5, 6}, {9, 10}}; !reflect.DeepEqual(cols.ToMatrix(), want) {
+		t.Errorf("Slice(1, 1, 3) = %v; want %v", cols.ToMatrix(), want)
+	}
+	cols.Set(-1, 2, 0)
+	if x.At(2, 1) != -1 {
+		t.Errorf("write through slice view: x[2][1] = %v; want -1", x.At(2, 1))
+	}
+
+	row, _ := x.Index(1)
+	row.Fill(7)
+	if want := []float64{7, 7, 7, 7}; !reflect.DeepEqual(x.Rows()[1], want) {
+		t.Errorf("write through Index view: row 1 = %v; want %v", x.Rows()[1], want)
+	}
+
+	xt := x.T()
+	if !reflect.DeepEqual(xt.Shape(), []int{4, 3}) || xt.At(3, 0) != x.At(0, 3) {
+		t.Errorf("T() shape %v, T[3][0] = %v; want [4 3], %v", xt.Shape(), xt.At(3, 0), x.At(0, 3))
+	}
+	if got := xt.Clone(); !got.IsContiguous() || got.At(1, 2) != x.At(2, 1) {
+		t.Errorf("Clone() of transpose = %v", got.ToMatrix())
+	}
+}
+
+func TestReshape(t *testing.T) {
+	x, _ := FromSlice([]float64{1, 2, 3, 4, 5, 6}, 2, 3)
+
+	y, err := x.Reshape(3, -1)
+	if err != nil {
+		t.Fatalf("Reshape() returned error: %v", err)
+	}
+	if !reflect.DeepEqual(y.Shape(), []int{3, 2}) {
+		t.Errorf("Reshape(3, -1) shape = %v; want [3 2]", y.Shape())
+	}
+	y.Set(0, 0, 0)
+	if x.At(0, 0) != 0 {
+		t.Error("Reshape() of a contiguous tensor did not return a view")
+	}
+
+	// A transposed view is not contiguous, so reshaping it copies.
+	z, err := x.T().Reshape(6)
+	if err != nil {
+		t.Fatalf("Reshape() returned error: %v", err)
+	}
+	if want := []float64{0, 4, 2, 5, 3, 6}; !reflect.DeepEqual(z.Data(), want) {
+		t.Errorf("T().Reshape(6) = %v; want %v", z.Data(), want)
+	}
+
+	if _, err := x.Reshape(4, -1); err == nil {
+		t.Error("Reshape(4, -1) expected error, got nil")
+	}
+}
+
+func TestMatMul(t *testing.T) {
+	a, _ := FromMatrix([][]float64{{1, 2}, {3, 4}, {5, 6}})
+	b, _ := FromMatrix([][]float64{{7, 8, 9}, {10, 11, 12}})
+
+	c, err := MatMul(a, b)
+	if err != nil {
+		t.Fatalf("MatMul() returned error: %v", err)
+	}
+	want := [][]float64{{27, 30, 33}, {61, 68, 75}, {95, 106, 117}}
+	if !reflect.DeepEqual(c.ToMatrix(), want) {
+		t.Errorf("MatMul() = %v; want %v", c.ToMatrix(), want)
+	}
+
+	// (aᵀ)ᵀ·(bᵀ)ᵀ through strided views gives the same product.
+	c2, err := MatMul(a.T().T(), b.T().T())
+	if err != nil || !reflect.DeepEqual(c2.ToMatrix(), want) {
+		t.Errorf("MatMul() on transposed views = %v, %v; want %v", c2, err, want)
+	}
+
+	// aᵀ·a uses a strided left operand.
+	ata, err := MatMul(a.T(), a)
+	if err != nil {
+		t.Fatalf("MatMul() returned error: %v", err)
+	}
+	if want := [][]float64{{35, 44}, {44, 56}}; !reflect.DeepEqual(ata.ToMatrix(), want) {
+		t.Errorf("MatMul(aᵀ, a) = %v; want %v", ata.ToMatrix(), want)
+	}
+
+	if _, err := MatMul(a, a); err == nil {
+		t.Error("MatMul() expected shape mismatch error, got nil")
+	}
+}
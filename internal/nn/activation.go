@@ -3,45 +3,89 @@ package nn
 import (
 	"fmt"
 	"math"
+	"sort"
 	"sync"
+
+	"github.com/SobhanYasami/nn-go/internal/mathx"
 )
 
 // ActivationType represents different activation functions
 type ActivationType string
 
 const (
-	ReLU      ActivationType = "relu"
-	Sigmoid   ActivationType = "sigmoid"
-	Tanh      ActivationType = "tanh"
-	Softmax   ActivationType = "softmax"
-	Linear    ActivationType = "linear"
-	LeakyReLU ActivationType = "leaky_relu"
-	ELU       ActivationType = "elu"
+	ReLU        ActivationType = "relu"
+	Sigmoid     ActivationType = "sigmoid"
+	Tanh        ActivationType = "tanh"
+	Softmax     ActivationType = "softmax"
+	LogSoftmax  ActivationType = "log_softmax"
+	Sparsemax   ActivationType = "sparsemax"
+	Linear      ActivationType = "linear"
+	LeakyReLU   ActivationType = "leaky_relu"
+	ELU         ActivationType = "elu"
+	GELU        ActivationType = "gelu"
+	GELUTanh    ActivationType = "gelu_tanh"
+	SiLU        ActivationType = "silu"
+	Mish        ActivationType = "mish"
+	Softplus    ActivationType = "softplus"
+	SELU        ActivationType = "selu"
+	HardSigmoid ActivationType = "hard_sigmoid"
+	HardSwish   ActivationType = "hard_swish"
+
+	// Swish is an alias for SiLU (Swish with β = 1).
+	Swish = SiLU
+)
+
+// SELU constants from Klambauer et al. (2017), chosen so activations
+// self-normalize towards zero mean and unit variance.
+const (
+	seluAlpha = 1.6732632423543772848170429916717
+	seluScale = 1.0507009873554804934193349852946
+)
+
+// Default slopes set by NewActivationFn and NewActivationLayer.
+const (
+	DefaultLeakyReLUAlpha = 0.01
+	DefaultELUAlpha       = 1.0
 )
 
 // ActivationFn provides activation functions and their derivatives
 type ActivationFn struct {
+	// LeakyReLUAlpha is the negative slope used for LeakyReLU
+	LeakyReLUAlpha float64
+	// ELUAlpha is the saturation value used for ELU
+	ELUAlpha float64
+	// SoftmaxTemperature divides the logits before Softmax (0 means 1)
+	SoftmaxTemperature float64
+
 	// Cache for storing intermediate values during forward pass for efficient backward pass
 	cache sync.Map
 }
 
-// ActivationResult holds both the output and a function to compute gradients
+// ActivationResult holds both the output and a function to compute gradients.
+// Backward returns nil when dOutputs does not have the shape of Output.
 type ActivationResult struct {
-	Output   [][]float64
-	Backward func(dOutputs [][]float64) [][]float64
+	Output   *mathx.Tensor
+	Backward func(dOutputs *mathx.Tensor) *mathx.Tensor
 }
 
-// NewActivationFn creates a new ActivationFn instance
+// NewActivationFn creates a new ActivationFn instance with the default
+// LeakyReLU and ELU alphas
 func NewActivationFn() *ActivationFn {
-	return &ActivationFn{}
+	return &ActivationFn{
+		LeakyReLUAlpha: DefaultLeakyReLUAlpha,
+		ELUAlpha:       DefaultELUAlpha,
+	}
 }
 
 //? ------------------------------
 //? Interface Methods
 //? ------------------------------
 
-// Apply applies the specified activation function to inputs
-func (af *ActivationFn) Apply(activation ActivationType, inputs [][]float64, inPlace bool) ([][]float64, error) {
+// Apply applies the specified activation function to inputs. Elementwise
+// activations accept tensors of any shape; Softmax, LogSoftmax and Sparsemax
+// normalize along the last axis. With inPlace set, inputs is overwritten
+// (views write through to their parent) and nil is returned.
+func (af *ActivationFn) Apply(activation ActivationType, inputs *mathx.Tensor, inPlace bool) (*mathx.Tensor, error) {
 	switch activation {
 	case ReLU:
 		if inPlace {
@@ -60,33 +104,49 @@ func (af *ActivationFn) Apply(activation ActivationType, inputs [][]float64, inP
 		return af.Tanh(inputs)
 	case Softmax:
 		if inPlace {
-			return nil, af.SoftmaxInPlace(inputs)
+			return nil, af.SoftmaxWithTemperatureInPlace(inputs, af.softmaxTemperature())
 		}
-		return af.Softmax(inputs)
+		return af.SoftmaxWithTemperature(inputs, af.softmaxTemperature())
+	case Sparsemax:
+		if inPlace {
+			return nil, af.SparsemaxInPlace(inputs)
+		}
+		return af.Sparsemax(inputs)
+	case LogSoftmax:
+		if inPlace {
+			return nil, af.LogSoftmaxInPlace(inputs)
+		}
+		return af.LogSoftmax(inputs)
 	case LeakyReLU:
 		if inPlace {
-			return nil, af.LeakyReLUInPlace(inputs, 0.01)
+			return nil, af.LeakyReLUInPlace(inputs, af.LeakyReLUAlpha)
 		}
-		return af.LeakyReLU(inputs, 0.01)
+		return af.LeakyReLU(inputs, af.LeakyReLUAlpha)
 	case ELU:
 		if inPlace {
-			return nil, af.ELUInPlace(inputs, 1.0)
+			return nil, af.ELUInPlace(inputs, af.ELUAlpha)
+		}
+		return af.ELU(inputs, af.ELUAlpha)
+	case GELU, GELUTanh, SiLU, Mish, Softplus, SELU, HardSigmoid, HardSwish:
+		f, _ := elementwiseActivation(activation)
+		if inPlace {
+			return nil, mapInPlace(inputs, f)
 		}
-		return af.ELU(inputs, 1.0)
+		return mapElementwise(inputs, f)
 	case Linear:
 		// Linear activation returns inputs as-is
 		if inPlace {
 			return nil, nil
 		}
-		return copyMatrix(inputs), nil
+		return inputs.Clone(), nil
 	default:
 		return nil, fmt.Errorf("unknown activation function: %s", activation)
 	}
 }
 
 // ApplyWithGrad applies activation and returns result with gradient function
-func (af *ActivationFn) ApplyWithGrad(activation ActivationType, inputs [][]float64) (*ActivationResult, error) {
-	var output [][]float64
+func (af *ActivationFn) ApplyWithGrad(activation ActivationType, inputs *mathx.Tensor) (*ActivationResult, error) {
+	var output *mathx.Tensor
 	var err error
 
 	switch activation {
@@ -97,7 +157,7 @@ func (af *ActivationFn) ApplyWithGrad(activation ActivationType, inputs [][]floa
 		}
 		return &ActivationResult{
 			Output: output,
-			Backward: func(dOutputs [][]float64) [][]float64 {
+			Backward: func(dOutputs *mathx.Tensor) *mathx.Tensor {
 				return af.ReLUBackward(dOutputs, inputs)
 			},
 		}, nil
@@ -109,7 +169,7 @@ func (af *ActivationFn) ApplyWithGrad(activation ActivationType, inputs [][]floa
 		}
 		return &ActivationResult{
 			Output: output,
-			Backward: func(dOutputs [][]float64) [][]float64 {
+			Backward: func(dOutputs *mathx.Tensor) *mathx.Tensor {
 				return af.SigmoidBackward(dOutputs, output)
 			},
 		}, nil
@@ -121,20 +181,95 @@ func (af *ActivationFn) ApplyWithGrad(activation ActivationType, inputs [][]floa
 		}
 		return &ActivationResult{
 			Output: output,
-			Backward: func(dOutputs [][]float64) [][]float64 {
+			Backward: func(dOutputs *mathx.Tensor) *mathx.Tensor {
 				return af.TanhBackward(dOutputs, output)
 			},
 		}, nil
 
 	case Softmax:
-		output, err = af.Softmax(inputs)
+		temperature := af.softmaxTemperature()
+		output, err = af.SoftmaxWithTemperature(inputs, temperature)
+		if err != nil {
+			return nil, err
+		}
+		return &ActivationResult{
+			Output: output,
+			Backward: func(dOutputs *mathx.Tensor) *mathx.Tensor {
+				return af.SoftmaxWithTemperatureBackward(dOutputs, output, temperature)
+			},
+		}, nil
+
+	case Sparsemax:
+		output, err = af.Sparsemax(inputs)
+		if err != nil {
+			return nil, err
+		}
+		return &ActivationResult{
+			Output: output,
+			Backward: func(dOutputs *mathx.Tensor) *mathx.Tensor {
+				return af.SparsemaxBackward(dOutputs, output)
+			},
+		}, nil
+
+	case LogSoftmax:
+		output, err = af.LogSoftmax(inputs)
+		if err != nil {
+			return nil, err
+		}
+		return &ActivationResult{
+			Output: output,
+			Backward: func(dOutputs *mathx.Tensor) *mathx.Tensor {
+				return af.LogSoftmaxBackward(dOutputs, output)
+			},
+		}, nil
+
+	case LeakyReLU:
+		alpha := af.LeakyReLUAlpha
+		output, err = af.LeakyReLU(inputs, alpha)
+		if err != nil {
+			return nil, err
+		}
+		return &ActivationResult{
+			Output: output,
+			Backward: func(dOutputs *mathx.Tensor) *mathx.Tensor {
+				return af.LeakyReLUBackward(dOutputs, inputs, alpha)
+			},
+		}, nil
+
+	case ELU:
+		alpha := af.ELUAlpha
+		output, err = af.ELU(inputs, alpha)
 		if err != nil {
 			return nil, err
 		}
 		return &ActivationResult{
 			Output: output,
-			Backward: func(dOutputs [][]float64) [][]float64 {
-				return af.SoftmaxBackward(dOutputs, output)
+			Backward: func(dOutputs *mathx.Tensor) *mathx.Tensor {
+				return af.ELUBackward(dOutputs, inputs, alpha)
+			},
+		}, nil
+
+	case Linear:
+		if err = validateTensor(inputs); err != nil {
+			return nil, err
+		}
+		return &ActivationResult{
+			Output: inputs.Clone(),
+			Backward: func(dOutputs *mathx.Tensor) *mathx.Tensor {
+				return af.LinearBackward(dOutputs, inputs)
+			},
+		}, nil
+
+	case GELU, GELUTanh, SiLU, Mish, Softplus, SELU, HardSigmoid, HardSwish:
+		f, df := elementwiseActivation(activation)
+		output, err = mapElementwise(inputs, f)
+		if err != nil {
+			return nil, err
+		}
+		return &ActivationResult{
+			Output: output,
+			Backward: func(dOutputs *mathx.Tensor) *mathx.Tensor {
+				return elementwiseBackward(dOutputs, inputs, df)
 			},
 		}, nil
 
@@ -148,240 +283,383 @@ func (af *ActivationFn) ApplyWithGrad(activation ActivationType, inputs [][]floa
 //? ------------------------------
 
 // ReLUInPlace applies ReLU(x) = max(0, x) elementwise in-place.
-func (af *ActivationFn) ReLUInPlace(inputs [][]float64) error {
-	if err := validateMatrix(inputs); err != nil {
-		return err
-	}
+func (af *ActivationFn) ReLUInPlace(inputs *mathx.Tensor) error {
+	return mapInPlace(inputs, relu)
+}
 
-	for i := range inputs {
-		for j := range inputs[i] {
-			if inputs[i][j] < 0 {
-				inputs[i][j] = 0
-			}
-		}
-	}
-	return nil
+// ReLU returns a new tensor (non-mutating version).
+func (af *ActivationFn) ReLU(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	return mapElementwise(inputs, relu)
 }
 
-// ReLU returns a new slice (non-mutating version).
-func (af *ActivationFn) ReLU(inputs [][]float64) ([][]float64, error) {
-	if err := validateMatrix(inputs); err != nil {
-		return nil, err
-	}
+// LeakyReLU applies Leaky ReLU: f(x) = x if x > 0, else alpha * x
+func (af *ActivationFn) LeakyReLU(inputs *mathx.Tensor, alpha float64) (*mathx.Tensor, error) {
+	return mapElementwise(inputs, leakyReLU(alpha))
+}
 
-	output := make([][]float64, len(inputs))
-	for i := range inputs {
-		output[i] = make([]float64, len(inputs[i]))
-		for j := range inputs[i] {
-			if inputs[i][j] > 0 {
-				output[i][j] = inputs[i][j]
-			}
-		}
-	}
-	return output, nil
+// LeakyReLUInPlace applies Leaky ReLU in-place
+func (af *ActivationFn) LeakyReLUInPlace(inputs *mathx.Tensor, alpha float64) error {
+	return mapInPlace(inputs, leakyReLU(alpha))
 }
 
-// LeakyReLU applies Leaky ReLU: f(x) = x if x > 0, else alpha * x
-func (af *ActivationFn) LeakyReLU(inputs [][]float64, alpha float64) ([][]float64, error) {
-	if err := validateMatrix(inputs); err != nil {
-		return nil, err
+// ELU applies Exponential Linear Unit: f(x) = x if x > 0, else alpha * (exp(x) - 1)
+func (af *ActivationFn) ELU(inputs *mathx.Tensor, alpha float64) (*mathx.Tensor, error) {
+	return mapElementwise(inputs, elu(alpha))
+}
+
+// ELUInPlace applies ELU in-place
+func (af *ActivationFn) ELUInPlace(inputs *mathx.Tensor, alpha float64) error {
+	return mapInPlace(inputs, elu(alpha))
+}
+
+// leakyReLU returns the scalar Leaky ReLU with slope alpha
+func leakyReLU(alpha float64) func(float64) float64 {
+	return func(x float64) float64 {
+		if x > 0 {
+			return x
+		}
+		return alpha * x
 	}
+}
 
-	output := make([][]float64, len(inputs))
-	for i := range inputs {
-		output[i] = make([]float64, len(inputs[i]))
-		for j := range inputs[i] {
-			if inputs[i][j] > 0 {
-				output[i][j] = inputs[i][j]
-			} else {
-				output[i][j] = alpha * inputs[i][j]
-			}
+// elu returns the scalar ELU with the given alpha
+func elu(alpha float64) func(float64) float64 {
+	return func(x float64) float64 {
+		if x > 0 {
+			return x
 		}
+		return alpha * (math.Exp(x) - 1)
 	}
-	return output, nil
 }
 
-// LeakyReLUInPlace applies Leaky ReLU in-place
-func (af *ActivationFn) LeakyReLUInPlace(inputs [][]float64, alpha float64) error {
-	if err := validateMatrix(inputs); err != nil {
+//? ------------------------------
+//? Sigmoid Activations
+//? ------------------------------
+
+// Sigmoid applies sigmoid activation: f(x) = 1 / (1 + exp(-x))
+func (af *ActivationFn) Sigmoid(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	return mapElementwise(inputs, sigmoid)
+}
+
+// SigmoidInPlace applies sigmoid activation in-place
+func (af *ActivationFn) SigmoidInPlace(inputs *mathx.Tensor) error {
+	return mapInPlace(inputs, sigmoid)
+}
+
+//? ------------------------------
+//? Tanh Activations
+//? ------------------------------
+
+// Tanh applies hyperbolic tangent activation: f(x) = tanh(x)
+func (af *ActivationFn) Tanh(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	return mapElementwise(inputs, math.Tanh)
+}
+
+// TanhInPlace applies tanh activation in-place
+func (af *ActivationFn) TanhInPlace(inputs *mathx.Tensor) error {
+	return mapInPlace(inputs, math.Tanh)
+}
+
+//? ------------------------------
+//? Softmax Activations
+//? ------------------------------
+
+// SoftmaxInPlace applies softmax activation in-place with numerical stability
+func (af *ActivationFn) SoftmaxInPlace(inputs *mathx.Tensor) error {
+	return af.SoftmaxWithTemperatureInPlace(inputs, 1)
+}
+
+// Softmax returns a new tensor with softmax applied along the last axis
+func (af *ActivationFn) Softmax(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	return af.SoftmaxWithTemperature(inputs, 1)
+}
+
+// SoftmaxWithTemperatureInPlace applies softmax(x / T) in-place. T > 1 flattens
+// the distribution, T < 1 sharpens it.
+func (af *ActivationFn) SoftmaxWithTemperatureInPlace(inputs *mathx.Tensor, temperature float64) error {
+	if err := validateTensor(inputs); err != nil {
 		return err
 	}
-
-	for i := range inputs {
-		for j := range inputs[i] {
-			if inputs[i][j] < 0 {
-				inputs[i][j] *= alpha
-			}
-		}
+	if err := validateTemperature(temperature); err != nil {
+		return err
 	}
+
+	rowsInPlace(inputs, func(row []float64) {
+		softmaxRow(row, row, temperature, nil)
+	})
 	return nil
 }
 
-// ELU applies Exponential Linear Unit: f(x) = x if x > 0, else alpha * (exp(x) - 1)
-func (af *ActivationFn) ELU(inputs [][]float64, alpha float64) ([][]float64, error) {
-	if err := validateMatrix(inputs); err != nil {
+// SoftmaxWithTemperature returns a new tensor with softmax(x / T) applied
+func (af *ActivationFn) SoftmaxWithTemperature(inputs *mathx.Tensor, temperature float64) (*mathx.Tensor, error) {
+	if err := validateTensor(inputs); err != nil {
 		return nil, err
 	}
 
-	output := make([][]float64, len(inputs))
-	for i := range inputs {
-		output[i] = make([]float64, len(inputs[i]))
-		for j := range inputs[i] {
-			if inputs[i][j] > 0 {
-				output[i][j] = inputs[i][j]
-			} else {
-				output[i][j] = alpha * (math.Exp(inputs[i][j]) - 1)
-			}
-		}
+	output := inputs.Clone()
+	if err := af.SoftmaxWithTemperatureInPlace(output, temperature); err != nil {
+		return nil, err
 	}
 	return output, nil
 }
 
-// ELUInPlace applies ELU in-place
-func (af *ActivationFn) ELUInPlace(inputs [][]float64, alpha float64) error {
-	if err := validateMatrix(inputs); err != nil {
+// MaskedSoftmaxInPlace applies softmax over the positions where mask is true;
+// masked-out positions become 0. Rows with no valid position become all zeros.
+// mask has one row per row of inputs (see Tensor.Rows).
+func (af *ActivationFn) MaskedSoftmaxInPlace(inputs *mathx.Tensor, mask [][]bool) error {
+	if err := validateTensor(inputs); err != nil {
 		return err
 	}
-
-	for i := range inputs {
-		for j := range inputs[i] {
-			if inputs[i][j] < 0 {
-				inputs[i][j] = alpha * (math.Exp(inputs[i][j]) - 1)
-			}
-		}
+	if err := validateMask(inputs, mask); err != nil {
+		return err
 	}
+
+	i := 0
+	rowsInPlace(inputs, func(row []float64) {
+		softmaxRow(row, row, 1, mask[i])
+		i++
+	})
 	return nil
 }
 
-//? ------------------------------
-//? Sigmoid Activations
-//? ------------------------------
-
-// Sigmoid applies sigmoid activation: f(x) = 1 / (1 + exp(-x))
-func (af *ActivationFn) Sigmoid(inputs [][]float64) ([][]float64, error) {
-	if err := validateMatrix(inputs); err != nil {
+// MaskedSoftmax returns a new tensor with masked softmax applied, e.g. to
+// ignore padded positions in attention scores
+func (af *ActivationFn) MaskedSoftmax(inputs *mathx.Tensor, mask [][]bool) (*mathx.Tensor, error) {
+	if err := validateTensor(inputs); err != nil {
 		return nil, err
 	}
 
-	output := make([][]float64, len(inputs))
-	for i := range inputs {
-		output[i] = make([]float64, len(inputs[i]))
-		for j := range inputs[i] {
-			output[i][j] = sigmoid(inputs[i][j])
-		}
+	output := inputs.Clone()
+	if err := af.MaskedSoftmaxInPlace(output, mask); err != nil {
+		return nil, err
 	}
 	return output, nil
 }
 
-// SigmoidInPlace applies sigmoid activation in-place
-func (af *ActivationFn) SigmoidInPlace(inputs [][]float64) error {
-	if err := validateMatrix(inputs); err != nil {
-		return err
+// softmaxRow writes softmax(src / temperature) into dst (which may alias src),
+// considering only the positions where mask is true (all positions if mask is nil).
+func softmaxRow(dst, src []float64, temperature float64, mask []bool) {
+	maxVal := math.Inf(-1)
+	for j, v := range src {
+		if mask == nil || mask[j] {
+			maxVal = math.Max(maxVal, v)
+		}
+	}
+	if math.IsInf(maxVal, -1) {
+		// Empty or fully masked row
+		for j := range dst {
+			dst[j] = 0
+		}
+		return
 	}
 
-	for i := range inputs {
-		for j := range inputs[i] {
-			inputs[i][j] = sigmoid(inputs[i][j])
+	// Compute exp((x - max) / T) and sum
+	var sum float64
+	for j, v := range src {
+		if mask != nil && !mask[j] {
+			dst[j] = 0
+			continue
 		}
+		dst[j] = math.Exp((v - maxVal) / temperature)
+		sum += dst[j]
+	}
+
+	// Normalize
+	for j := range dst {
+		dst[j] /= sum
 	}
-	return nil
 }
 
 //? ------------------------------
-//? Tanh Activations
+//? Sparsemax Activations
 //? ------------------------------
 
-// Tanh applies hyperbolic tangent activation: f(x) = tanh(x)
-func (af *ActivationFn) Tanh(inputs [][]float64) ([][]float64, error) {
-	if err := validateMatrix(inputs); err != nil {
-		return nil, err
+// SparsemaxInPlace applies sparsemax in-place: the Euclidean projection of
+// each row onto the probability simplex (Martins & Astudillo, 2016). Unlike
+// softmax it assigns exactly zero probability to low-scoring entries.
+func (af *ActivationFn) SparsemaxInPlace(inputs *mathx.Tensor) error {
+	if err := validateTensor(inputs); err != nil {
+		return err
 	}
 
-	output := make([][]float64, len(inputs))
-	for i := range inputs {
-		output[i] = make([]float64, len(inputs[i]))
-		for j := range inputs[i] {
-			output[i][j] = math.Tanh(inputs[i][j])
+	rowsInPlace(inputs, func(row []float64) {
+		tau := sparsemaxThreshold(row)
+		for j := range row {
+			row[j] = math.Max(row[j]-tau, 0)
 		}
+	})
+	return nil
+}
+
+// Sparsemax returns a new tensor with sparsemax applied
+func (af *ActivationFn) Sparsemax(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	if err := validateTensor(inputs); err != nil {
+		return nil, err
+	}
+
+	output := inputs.Clone()
+	if err := af.SparsemaxInPlace(output); err != nil {
+		return nil, err
 	}
 	return output, nil
 }
 
-// TanhInPlace applies tanh activation in-place
-func (af *ActivationFn) TanhInPlace(inputs [][]float64) error {
-	if err := validateMatrix(inputs); err != nil {
+// sparsemaxThreshold returns τ such that Σ max(z_j - τ, 0) = 1.
+func sparsemaxThreshold(z []float64) float64 {
+	sorted := append([]float64(nil), z...)
+	sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+
+	var cumSum, tau float64
+	for k, v := range sorted {
+		cumSum += v
+		// z_(k) stays in the support while 1 + (k+1)·z_(k) > Σ_{j≤k} z_(j)
+		if 1+float64(k+1)*v > cumSum {
+			tau = (cumSum - 1) / float64(k+1)
+		} else {
+			break
+		}
+	}
+	return tau
+}
+
+// LogSoftmaxInPlace applies log-softmax in-place: x - max - log(Σ exp(x - max))
+func (af *ActivationFn) LogSoftmaxInPlace(inputs *mathx.Tensor) error {
+	if err := validateTensor(inputs); err != nil {
 		return err
 	}
 
-	for i := range inputs {
-		for j := range inputs[i] {
-			inputs[i][j] = math.Tanh(inputs[i][j])
+	rowsInPlace(inputs, func(row []float64) {
+		logSumExp := logSumExp(row)
+		for j := range row {
+			row[j] -= logSumExp
 		}
-	}
+	})
 	return nil
 }
 
+// LogSoftmax returns a new tensor with log-softmax applied. It is computed
+// directly from the inputs, so it stays finite where log(Softmax(x)) would
+// underflow to -Inf.
+func (af *ActivationFn) LogSoftmax(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	if err := validateTensor(inputs); err != nil {
+		return nil, err
+	}
+
+	output := inputs.Clone()
+	if err := af.LogSoftmaxInPlace(output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
 //? ------------------------------
-//? Softmax Activations
+//? Smooth & Self-Normalizing Activations
 //? ------------------------------
 
-// SoftmaxInPlace applies softmax activation in-place with numerical stability
-func (af *ActivationFn) SoftmaxInPlace(inputs [][]float64) error {
-	if err := validateMatrix(inputs); err != nil {
-		return err
+// elementwiseActivation returns the scalar function and derivative (with
+// respect to the input) of an elementwise activation.
+func elementwiseActivation(activation ActivationType) (f, df func(float64) float64) {
+	switch activation {
+	case GELU:
+		return gelu, geluDerivative
+	case GELUTanh:
+		return geluTanh, geluTanhDerivative
+	case SiLU:
+		return silu, siluDerivative
+	case Mish:
+		return mish, mishDerivative
+	case Softplus:
+		return softplus, stableSigmoid
+	case SELU:
+		return selu, seluDerivative
+	case HardSigmoid:
+		return hardSigmoid, hardSigmoidDerivative
+	case HardSwish:
+		return hardSwish, hardSwishDerivative
 	}
+	return nil, nil
+}
 
-	for i := range inputs {
-		if len(inputs[i]) == 0 {
-			continue
-		}
+// GELU applies the exact Gaussian Error Linear Unit: f(x) = x * Φ(x),
+// where Φ is the standard normal CDF.
+func (af *ActivationFn) GELU(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	return mapElementwise(inputs, gelu)
+}
 
-		maxVal := findMax(inputs[i])
-		var sum float64
+// GELUInPlace applies exact GELU in-place
+func (af *ActivationFn) GELUInPlace(inputs *mathx.Tensor) error {
+	return mapInPlace(inputs, gelu)
+}
 
-		// Compute exp(x - max) and sum
-		for j := range inputs[i] {
-			inputs[i][j] = math.Exp(inputs[i][j] - maxVal)
-			sum += inputs[i][j]
-		}
+// GELUTanh applies the tanh approximation of GELU:
+// f(x) = 0.5x * (1 + tanh(√(2/π) * (x + 0.044715x³)))
+func (af *ActivationFn) GELUTanh(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	return mapElementwise(inputs, geluTanh)
+}
 
-		// Normalize
-		for j := range inputs[i] {
-			inputs[i][j] /= sum
-		}
-	}
-	return nil
+// GELUTanhInPlace applies tanh-approximated GELU in-place
+func (af *ActivationFn) GELUTanhInPlace(inputs *mathx.Tensor) error {
+	return mapInPlace(inputs, geluTanh)
 }
 
-// Softmax returns a new slice with softmax applied
-func (af *ActivationFn) Softmax(inputs [][]float64) ([][]float64, error) {
-	if err := validateMatrix(inputs); err != nil {
-		return nil, err
-	}
+// SiLU applies the Sigmoid Linear Unit (Swish): f(x) = x * sigmoid(x)
+func (af *ActivationFn) SiLU(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	return mapElementwise(inputs, silu)
+}
 
-	output := make([][]float64, len(inputs))
-	for i := range inputs {
-		if len(inputs[i]) == 0 {
-			output[i] = []float64{}
-			continue
-		}
+// SiLUInPlace applies SiLU in-place
+func (af *ActivationFn) SiLUInPlace(inputs *mathx.Tensor) error {
+	return mapInPlace(inputs, silu)
+}
 
-		maxVal := findMax(inputs[i])
-		output[i] = make([]float64, len(inputs[i]))
-		var sum float64
+// Mish applies f(x) = x * tanh(softplus(x))
+func (af *ActivationFn) Mish(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	return mapElementwise(inputs, mish)
+}
 
-		// Compute exp(x - max) and sum
-		for j := range inputs[i] {
-			output[i][j] = math.Exp(inputs[i][j] - maxVal)
-			sum += output[i][j]
-		}
+// MishInPlace applies Mish in-place
+func (af *ActivationFn) MishInPlace(inputs *mathx.Tensor) error {
+	return mapInPlace(inputs, mish)
+}
 
-		// Normalize
-		for j := range output[i] {
-			output[i][j] /= sum
-		}
-	}
-	return output, nil
+// Softplus applies f(x) = log(1 + exp(x)), computed without overflow
+func (af *ActivationFn) Softplus(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	return mapElementwise(inputs, softplus)
+}
+
+// SoftplusInPlace applies Softplus in-place
+func (af *ActivationFn) SoftplusInPlace(inputs *mathx.Tensor) error {
+	return mapInPlace(inputs, softplus)
+}
+
+// SELU applies the Scaled ELU: f(x) = λx if x > 0, else λα(exp(x) - 1)
+func (af *ActivationFn) SELU(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	return mapElementwise(inputs, selu)
+}
+
+// SELUInPlace applies SELU in-place
+func (af *ActivationFn) SELUInPlace(inputs *mathx.Tensor) error {
+	return mapInPlace(inputs, selu)
+}
+
+// HardSigmoid applies the piecewise-linear sigmoid: f(x) = clamp(x/6 + 1/2, 0, 1)
+func (af *ActivationFn) HardSigmoid(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	return mapElementwise(inputs, hardSigmoid)
+}
+
+// HardSigmoidInPlace applies HardSigmoid in-place
+func (af *ActivationFn) HardSigmoidInPlace(inputs *mathx.Tensor) error {
+	return mapInPlace(inputs, hardSigmoid)
+}
+
+// HardSwish applies f(x) = x * HardSigmoid(x)
+func (af *ActivationFn) HardSwish(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	return mapElementwise(inputs, hardSwish)
+}
+
+// HardSwishInPlace applies HardSwish in-place
+func (af *ActivationFn) HardSwishInPlace(inputs *mathx.Tensor) error {
+	return mapInPlace(inputs, hardSwish)
 }
 
 //? ------------------------------
@@ -389,111 +667,212 @@ func (af *ActivationFn) Softmax(inputs [][]float64) ([][]float64, error) {
 //? ------------------------------
 
 // ReLUBackward computes gradient for ReLU activation
-func (af *ActivationFn) ReLUBackward(dOutputs, inputs [][]float64) [][]float64 {
-	if err := validateGradients(dOutputs, inputs); err != nil {
-		return nil
-	}
+func (af *ActivationFn) ReLUBackward(dOutputs, inputs *mathx.Tensor) *mathx.Tensor {
+	return elementwiseBackward(dOutputs, inputs, reluDerivative)
+}
 
-	dInputs := make([][]float64, len(dOutputs))
-	for i := range dOutputs {
-		dInputs[i] = make([]float64, len(dOutputs[i]))
-		for j := range dOutputs[i] {
-			if inputs[i][j] > 0 {
-				dInputs[i][j] = dOutputs[i][j]
-			}
+// LeakyReLUBackward computes gradient for Leaky ReLU activation
+func (af *ActivationFn) LeakyReLUBackward(dOutputs, inputs *mathx.Tensor, alpha float64) *mathx.Tensor {
+	return elementwiseBackward(dOutputs, inputs, func(x float64) float64 {
+		if x > 0 {
+			return 1
 		}
-	}
-	return dInputs
+		return alpha
+	})
 }
 
-// LeakyReLUBackward computes gradient for Leaky ReLU activation
-func (af *ActivationFn) LeakyReLUBackward(dOutputs, inputs [][]float64, alpha float64) [][]float64 {
+// ELUBackward computes gradient for ELU activation:
+// 1 if x > 0, else alpha * exp(x)
+func (af *ActivationFn) ELUBackward(dOutputs, inputs *mathx.Tensor, alpha float64) *mathx.Tensor {
+	return elementwiseBackward(dOutputs, inputs, func(x float64) float64 {
+		if x > 0 {
+			return 1
+		}
+		return alpha * math.Exp(x)
+	})
+}
+
+// LinearBackward computes gradient for the identity activation (a copy of dOutputs)
+func (af *ActivationFn) LinearBackward(dOutputs, inputs *mathx.Tensor) *mathx.Tensor {
 	if err := validateGradients(dOutputs, inputs); err != nil {
 		return nil
 	}
+	return dOutputs.Clone()
+}
 
-	dInputs := make([][]float64, len(dOutputs))
-	for i := range dOutputs {
-		dInputs[i] = make([]float64, len(dOutputs[i]))
-		for j := range dOutputs[i] {
-			if inputs[i][j] > 0 {
-				dInputs[i][j] = dOutputs[i][j]
-			} else {
-				dInputs[i][j] = alpha * dOutputs[i][j]
-			}
+// SigmoidBackward computes gradient for sigmoid activation
+func (af *ActivationFn) SigmoidBackward(dOutputs, outputs *mathx.Tensor) *mathx.Tensor {
+	return elementwiseBackward(dOutputs, outputs, sigmoidDerivative)
+}
+
+// TanhBackward computes gradient for tanh activation
+func (af *ActivationFn) TanhBackward(dOutputs, outputs *mathx.Tensor) *mathx.Tensor {
+	return elementwiseBackward(dOutputs, outputs, func(y float64) float64 {
+		return 1 - y*y
+	})
+}
+
+// SoftmaxBackward computes the vector-Jacobian product of softmax:
+// dx_j = y_j * (dy_j - Σ_k dy_k * y_k)
+func (af *ActivationFn) SoftmaxBackward(dOutputs, outputs *mathx.Tensor) *mathx.Tensor {
+	return rowwiseBackward(dOutputs, outputs, func(dx, dy, y []float64) {
+		var dot float64
+		for j := range dy {
+			dot += dy[j] * y[j]
 		}
-	}
-	return dInputs
+		for j := range dy {
+			dx[j] = y[j] * (dy[j] - dot)
+		}
+	})
 }
 
-// SigmoidBackward computes gradient for sigmoid activation
-func (af *ActivationFn) SigmoidBackward(dOutputs, outputs [][]float64) [][]float64 {
-	if err := validateGradients(dOutputs, outputs); err != nil {
+// SoftmaxWithTemperatureBackward computes the gradient of softmax(x / T):
+// the softmax vector-Jacobian product divided by T
+func (af *ActivationFn) SoftmaxWithTemperatureBackward(dOutputs, outputs *mathx.Tensor, temperature float64) *mathx.Tensor {
+	if validateTemperature(temperature) != nil {
 		return nil
 	}
-
-	dInputs := make([][]float64, len(dOutputs))
-	for i := range dOutputs {
-		dInputs[i] = make([]float64, len(dOutputs[i]))
-		for j := range dOutputs[i] {
-			dInputs[i][j] = dOutputs[i][j] * outputs[i][j] * (1 - outputs[i][j])
-		}
+	dInputs := af.SoftmaxBackward(dOutputs, outputs)
+	if dInputs != nil {
+		dInputs.Apply(func(d float64) float64 { return d / temperature })
 	}
 	return dInputs
 }
 
-// TanhBackward computes gradient for tanh activation
-func (af *ActivationFn) TanhBackward(dOutputs, outputs [][]float64) [][]float64 {
-	if err := validateGradients(dOutputs, outputs); err != nil {
-		return nil
-	}
+// MaskedSoftmaxBackward computes gradient for masked softmax. Masked-out
+// positions have zero output and therefore receive zero gradient.
+func (af *ActivationFn) MaskedSoftmaxBackward(dOutputs, outputs *mathx.Tensor) *mathx.Tensor {
+	return af.SoftmaxBackward(dOutputs, outputs)
+}
 
-	dInputs := make([][]float64, len(dOutputs))
-	for i := range dOutputs {
-		dInputs[i] = make([]float64, len(dOutputs[i]))
-		for j := range dOutputs[i] {
-			dInputs[i][j] = dOutputs[i][j] * (1 - outputs[i][j]*outputs[i][j])
+// SparsemaxBackward computes gradient for sparsemax. With S the support
+// (outputs > 0): dx_j = dy_j - mean_{k∈S}(dy_k) for j ∈ S, else 0
+func (af *ActivationFn) SparsemaxBackward(dOutputs, outputs *mathx.Tensor) *mathx.Tensor {
+	return rowwiseBackward(dOutputs, outputs, func(dx, dy, y []float64) {
+		var sum float64
+		var support int
+		for j := range y {
+			if y[j] > 0 {
+				sum += dy[j]
+				support++
+			}
 		}
-	}
-	return dInputs
+		if support == 0 {
+			return
+		}
+		mean := sum / float64(support)
+		for j := range y {
+			if y[j] > 0 {
+				dx[j] = dy[j] - mean
+			}
+		}
+	})
 }
 
-// SoftmaxBackward computes gradient for softmax activation (assumes cross-entropy loss)
-func (af *ActivationFn) SoftmaxBackward(dOutputs, outputs [][]float64) [][]float64 {
-	if err := validateGradients(dOutputs, outputs); err != nil {
-		return nil
-	}
+// LogSoftmaxBackward computes the vector-Jacobian product of log-softmax:
+// dx_j = dy_j - exp(y_j) * Σ_k dy_k
+func (af *ActivationFn) LogSoftmaxBackward(dOutputs, outputs *mathx.Tensor) *mathx.Tensor {
+	return rowwiseBackward(dOutputs, outputs, func(dx, dy, y []float64) {
+		var sum float64
+		for _, d := range dy {
+			sum += d
+		}
+		for j := range dy {
+			dx[j] = dy[j] - math.Exp(y[j])*sum
+		}
+	})
+}
+
+// GELUBackward computes gradient for exact GELU: Φ(x) + x * φ(x)
+func (af *ActivationFn) GELUBackward(dOutputs, inputs *mathx.Tensor) *mathx.Tensor {
+	return elementwiseBackward(dOutputs, inputs, geluDerivative)
+}
+
+// GELUTanhBackward computes gradient for tanh-approximated GELU
+func (af *ActivationFn) GELUTanhBackward(dOutputs, inputs *mathx.Tensor) *mathx.Tensor {
+	return elementwiseBackward(dOutputs, inputs, geluTanhDerivative)
+}
 
-	// For softmax + cross-entropy, the gradient is simply (output - target)
-	// which is already provided in dOutputs
-	return dOutputs
+// SiLUBackward computes gradient for SiLU: σ(x) * (1 + x * (1 - σ(x)))
+func (af *ActivationFn) SiLUBackward(dOutputs, inputs *mathx.Tensor) *mathx.Tensor {
+	return elementwiseBackward(dOutputs, inputs, siluDerivative)
+}
+
+// MishBackward computes gradient for Mish
+func (af *ActivationFn) MishBackward(dOutputs, inputs *mathx.Tensor) *mathx.Tensor {
+	return elementwiseBackward(dOutputs, inputs, mishDerivative)
+}
+
+// SoftplusBackward computes gradient for Softplus: sigmoid(x)
+func (af *ActivationFn) SoftplusBackward(dOutputs, inputs *mathx.Tensor) *mathx.Tensor {
+	return elementwiseBackward(dOutputs, inputs, stableSigmoid)
+}
+
+// SELUBackward computes gradient for SELU
+func (af *ActivationFn) SELUBackward(dOutputs, inputs *mathx.Tensor) *mathx.Tensor {
+	return elementwiseBackward(dOutputs, inputs, seluDerivative)
+}
+
+// HardSigmoidBackward computes gradient for HardSigmoid: 1/6 inside (-3, 3), else 0
+func (af *ActivationFn) HardSigmoidBackward(dOutputs, inputs *mathx.Tensor) *mathx.Tensor {
+	return elementwiseBackward(dOutputs, inputs, hardSigmoidDerivative)
+}
+
+// HardSwishBackward computes gradient for HardSwish
+func (af *ActivationFn) HardSwishBackward(dOutputs, inputs *mathx.Tensor) *mathx.Tensor {
+	return elementwiseBackward(dOutputs, inputs, hardSwishDerivative)
 }
 
 //? ------------------------------
 //? Utility Functions
 //? ------------------------------
 
-// validateMatrix checks if the input matrix is valid
-func validateMatrix(matrix [][]float64) error {
-	if len(matrix) == 0 {
-		return fmt.Errorf("matrix cannot be empty")
+// softmaxTemperature returns the configured softmax temperature or 1
+func (af *ActivationFn) softmaxTemperature() float64 {
+	if af.SoftmaxTemperature == 0 {
+		return 1
+	}
+	return af.SoftmaxTemperature
+}
+
+// validateTemperature checks that a softmax temperature is positive and finite
+func validateTemperature(temperature float64) error {
+	if !(temperature > 0) || math.IsInf(temperature, 1) {
+		return fmt.Errorf("temperature must be positive, got %v", temperature)
 	}
-	cols := len(matrix[0])
-	for i := range matrix {
-		if len(matrix[i]) != cols {
-			return fmt.Errorf("matrix has inconsistent column lengths")
+	return nil
+}
+
+// validateMask checks that mask has one row per row of inputs, of the same length
+func validateMask(inputs *mathx.Tensor, mask [][]bool) error {
+	cols := inputs.Dim(inputs.Dims() - 1)
+	rows := inputs.Size() / cols
+	if len(mask) != rows {
+		return fmt.Errorf("mask has %d rows, expected %d", len(mask), rows)
+	}
+	for i := range mask {
+		if len(mask[i]) != cols {
+			return fmt.Errorf("mask row %d has %d values, expected %d", i, len(mask[i]), cols)
 		}
 	}
 	return nil
 }
 
-// validateGradients checks if gradient matrices are compatible
-func validateGradients(dOutputs, inputs [][]float64) error {
-	if len(dOutputs) != len(inputs) {
-		return fmt.Errorf("gradient and input have different batch sizes")
+// validateTensor checks that an activation input is a non-empty tensor
+func validateTensor(t *mathx.Tensor) error {
+	if t == nil || t.Dims() == 0 || t.Size() == 0 {
+		return fmt.Errorf("tensor cannot be empty")
+	}
+	return nil
+}
+
+// validateGradients checks if gradient tensors are compatible
+func validateGradients(dOutputs, inputs *mathx.Tensor) error {
+	if dOutputs == nil || inputs == nil {
+		return fmt.Errorf("gradient and input cannot be nil")
 	}
-	if len(dOutputs) > 0 && len(dOutputs[0]) != len(inputs[0]) {
-		return fmt.Errorf("gradient and input have different feature dimensions")
+	if !mathx.SameShape(dOutputs, inputs) {
+		return fmt.Errorf("gradient shape %v does not match input shape %v", dOutputs.Shape(), inputs.Shape())
 	}
 	return nil
 }
@@ -512,6 +891,79 @@ func findMax(slice []float64) float64 {
 	return max
 }
 
+// mapElementwise returns a new tensor with f applied to every element
+func mapElementwise(inputs *mathx.Tensor, f func(float64) float64) (*mathx.Tensor, error) {
+	if err := validateTensor(inputs); err != nil {
+		return nil, err
+	}
+	return inputs.Map(f), nil
+}
+
+// mapInPlace applies f to every element in-place
+func mapInPlace(inputs *mathx.Tensor, f func(float64) float64) error {
+	if err := validateTensor(inputs); err != nil {
+		return err
+	}
+	inputs.Apply(f)
+	return nil
+}
+
+// rowsInPlace calls fn on every row of t (split along the last axis) and
+// writes the results back, also when t is a strided view.
+func rowsInPlace(t *mathx.Tensor, fn func(row []float64)) {
+	if t.IsContiguous() {
+		for _, row := range t.Rows() {
+			fn(row)
+		}
+		return
+	}
+	tmp := t.Clone()
+	for _, row := range tmp.Rows() {
+		fn(row)
+	}
+	t.CopyFrom(tmp)
+}
+
+// elementwiseBackward multiplies dOutputs by the derivative df evaluated at
+// inputs (or outputs, for activations whose derivative is cheaper from them)
+func elementwiseBackward(dOutputs, inputs *mathx.Tensor, df func(float64) float64) *mathx.Tensor {
+	if err := validateGradients(dOutputs, inputs); err != nil {
+		return nil
+	}
+
+	dInputs := dOutputs.Clone()
+	d, x := dInputs.Data(), inputs.Data()
+	for i := range d {
+		d[i] *= df(x[i])
+	}
+	return dInputs
+}
+
+// rowwiseBackward builds dInputs row by row with vjp(dx, dy, y), for
+// activations that normalize along the last axis.
+func rowwiseBackward(dOutputs, outputs *mathx.Tensor, vjp func(dx, dy, y []float64)) *mathx.Tensor {
+	if err := validateGradients(dOutputs, outputs); err != nil {
+		return nil
+	}
+
+	dInputs := mathx.NewTensor(dOutputs.Shape()...)
+	dx, dy, y := dInputs.Rows(), dOutputs.Rows(), outputs.Rows()
+	for i := range dx {
+		vjp(dx[i], dy[i], y[i])
+	}
+	return dInputs
+}
+
+// logSumExp returns log(Σ exp(x)) computed without overflow
+func logSumExp(x []float64) float64 {
+	maxVal := findMax(x)
+	var sum float64
+	for _, v := range x {
+		sum += math.Exp(v - maxVal)
+	}
+	return maxVal + math.Log(sum)
+}
+
 // copyMatrix creates a deep copy of a matrix
 func copyMatrix(matrix [][]float64) [][]float64 {
 	result := make([][]float64, len(matrix))
@@ -562,3 +1014,86 @@ func tanh(x float64) float64 {
 func tanhDerivative(x float64) float64 {
 	return 1.0 - math.Pow(math.Tanh(x), 2)
 }
+
+// geluTanhCoeff is √(2/π), used by the tanh approximation of GELU.
+var geluTanhCoeff = math.Sqrt(2 / math.Pi)
+
+func gelu(x float64) float64 {
+	return 0.5 * x * (1 + math.Erf(x/math.Sqrt2))
+}
+
+func geluDerivative(x float64) float64 {
+	cdf := 0.5 * (1 + math.Erf(x/math.Sqrt2))
+	pdf := math.Exp(-0.5*x*x) / math.Sqrt(2*math.Pi)
+	return cdf + x*pdf
+}
+
+func geluTanh(x float64) float64 {
+	return 0.5 * x * (1 + math.Tanh(geluTanhCoeff*(x+0.044715*x*x*x)))
+}
+
+func geluTanhDerivative(x float64) float64 {
+	t := math.Tanh(geluTanhCoeff * (x + 0.044715*x*x*x))
+	return 0.5*(1+t) + 0.5*x*(1-t*t)*geluTanhCoeff*(1+3*0.044715*x*x)
+}
+
+func silu(x float64) float64 {
+	return x * stableSigmoid(x)
+}
+
+func siluDerivative(x float64) float64 {
+	s := stableSigmoid(x)
+	return s * (1 + x*(1-s))
+}
+
+func softplus(x float64) float64 {
+	return math.Max(x, 0) + math.Log1p(math.Exp(-math.Abs(x)))
+}
+
+func mish(x float64) float64 {
+	return x * math.Tanh(softplus(x))
+}
+
+func mishDerivative(x float64) float64 {
+	t := math.Tanh(softplus(x))
+	return t + x*(1-t*t)*stableSigmoid(x)
+}
+
+func selu(x float64) float64 {
+	if x > 0 {
+		return seluScale * x
+	}
+	return seluScale * seluAlpha * (math.Exp(x) - 1)
+}
+
+func seluDerivative(x float64) float64 {
+	if x > 0 {
+		return seluScale
+	}
+	return seluScale * seluAlpha * math.Exp(x)
+}
+
+func hardSigmoid(x float64) float64 {
+	return math.Min(math.Max(x/6+0.5, 0), 1)
+}
+
+func hardSigmoidDerivative(x float64) float64 {
+	if x > -3 && x < 3 {
+		return 1.0 / 6
+	}
+	return 0
+}
+
+func hardSwish(x float64) float64 {
+	return x * hardSigmoid(x)
+}
+
+func hardSwishDerivative(x float64) float64 {
+	switch {
+	case x <= -3:
+		return 0
+	case x >= 3:
+		return 1
+	}
+	return (2*x + 3) / 6
+}
@@ -0,0 +1,257 @@
+package nn
+
+import (
+	"math"
+	"testing"
+
+	"github.com/SobhanYasami/nn-go/internal/mathx"
+)
+
+// gradInputs avoids the kinks of ReLU-like activations at 0 and ±3 and the
+// support boundaries of sparsemax.
+var gradInputs = [][]float64{
+	{-4.2, -2.5, -0.6, 0.3},
+	{1.1, 2.9, 3.5, -3.2},
+}
+
+// upstream is a fixed dL/dOutput so the checked loss is L = Σ upstream ⊙ f(x).
+var upstream = [][]float64{
+	{0.3, -1.0, 0.5, 2.0},
+	{1.0, 0.7, -0.4, 0.9},
+}
+
+// numericalGradient returns the central-difference gradient of L = Σ upstream ⊙ f(x).
+func numericalGradient(t *testing.T, af *ActivationFn, activation ActivationType, inputs [][]float64) [][]float64 {
+	t.Helper()
+	const h = 1e-6
+
+	loss := func(x [][]float64) float64 {
+		out, err := af.Apply(activation, tensor(t, x), false)
+		if err != nil {
+			t.Fatalf("Apply(%s) returned error: %v", activation, err)
+		}
+		var sum float64
+		for i, row := range out.Rows() {
+			for j := range row {
+				sum += upstream[i][j] * row[j]
+			}
+		}
+		return sum
+	}
+
+	x := copyMatrix(inputs)
+	grad := make([][]float64, len(x))
+	for i := range x {
+		grad[i] = make([]float64, len(x[i]))
+		for j := range x[i] {
+			orig := x[i][j]
+			x[i][j] = orig + h
+			plus := loss(x)
+			x[i][j] = orig - h
+			minus := loss(x)
+			x[i][j] = orig
+			grad[i][j] = (plus - minus) / (2 * h)
+		}
+	}
+	return grad
+}
+
+func TestApplyWithGradMatchesNumericalGradient(t *testing.T) {
+	tests := []struct {
+		activation ActivationType
+		af         *ActivationFn
+	}{
+		{ReLU, NewActivationFn()},
+		{Sigmoid, NewActivationFn()},
+		{Tanh, NewActivationFn()},
+		{Softmax, NewActivationFn()},
+		{Softmax, &ActivationFn{SoftmaxTemperature: 0.5}},
+		{LogSoftmax, NewActivationFn()},
+		{Sparsemax, NewActivationFn()},
+		{Linear, NewActivationFn()},
+		{LeakyReLU, NewActivationFn()},
+		{LeakyReLU, &ActivationFn{LeakyReLUAlpha: 0.2}},
+		{ELU, NewActivationFn()},
+		{ELU, &ActivationFn{ELUAlpha: 0.5}},
+		{GELU, NewActivationFn()},
+		{GELUTanh, NewActivationFn()},
+		{SiLU, NewActivationFn()},
+		{Mish, NewActivationFn()},
+		{Softplus, NewActivationFn()},
+		{SELU, NewActivationFn()},
+		{HardSigmoid, NewActivationFn()},
+		{HardSwish, NewActivationFn()},
+	}
+
+	for _, tt := range tests {
+		result, err := tt.af.ApplyWithGrad(tt.activation, tensor(t, gradInputs))
+		if err != nil {
+			t.Errorf("ApplyWithGrad(%s) returned error: %v", tt.activation, err)
+			continue
+		}
+		got := result.Backward(tensor(t, upstream)).Rows()
+		want := numericalGradient(t, tt.af, tt.activation, gradInputs)
+		for i := range want {
+			for j := range want[i] {
+				if !almostEqual(got[i][j], want[i][j], 1e-6) {
+					t.Errorf("%s (leaky α=%v, elu α=%v): grad[%d][%d] = %v; want %v",
+						tt.activation, tt.af.LeakyReLUAlpha, tt.af.ELUAlpha, i, j, got[i][j], want[i][j])
+				}
+			}
+		}
+	}
+}
+
+func TestApplyInPlaceMatchesApply(t *testing.T) {
+	af := NewActivationFn()
+	for _, activation := range []ActivationType{
+		ReLU, Sigmoid, Tanh, Softmax, LogSoftmax, Sparsemax, Linear, LeakyReLU, ELU,
+		GELU, GELUTanh, SiLU, Mish, Softplus, SELU, HardSigmoid, HardSwish,
+	} {
+		out, err := af.Apply(activation, tensor(t, gradInputs), false)
+		if err != nil {
+			t.Fatalf("Apply(%s) returned error: %v", activation, err)
+		}
+		want := out.Rows()
+		x := tensor(t, gradInputs)
+		if _, err := af.Apply(activation, x, true); err != nil {
+			t.Fatalf("Apply(%s, inPlace) returned error: %v", activation, err)
+		}
+		got := x.Rows()
+		for i := range want {
+			for j := range want[i] {
+				if !almostEqual(got[i][j], want[i][j], 1e-12) {
+					t.Errorf("%s in-place [%d][%d] = %v; want %v", activation, i, j, got[i][j], want[i][j])
+				}
+			}
+		}
+	}
+}
+
+func TestActivationAlpha(t *testing.T) {
+	inputs := tensor(t, [][]float64{{-2}})
+	tests := []struct {
+		af         *ActivationFn
+		activation ActivationType
+		want       float64
+	}{
+		{NewActivationFn(), LeakyReLU, -2 * DefaultLeakyReLUAlpha},
+		{&ActivationFn{LeakyReLUAlpha: 0.3}, LeakyReLU, -0.6},
+		{NewActivationFn(), ELU, DefaultELUAlpha * (math.Exp(-2) - 1)},
+		{&ActivationFn{ELUAlpha: 2}, ELU, 2 * (math.Exp(-2) - 1)},
+		// A zero alpha is a zero slope, not a request for the default.
+		{&ActivationFn{}, LeakyReLU, 0},
+		{&ActivationFn{}, ELU, 0},
+	}
+
+	for _, tt := range tests {
+		out, err := tt.af.Apply(tt.activation, inputs, false)
+		if err != nil {
+			t.Fatalf("Apply(%s) returned error: %v", tt.activation, err)
+		}
+		if !almostEqual(out.At(0, 0), tt.want, 1e-12) {
+			t.Errorf("%s(-2) = %v; want %v", tt.activation, out.At(0, 0), tt.want)
+		}
+	}
+
+	layer, err := NewActivationLayerWithAlpha(LeakyReLU, 0.3)
+	if err != nil {
+		t.Fatalf("NewActivationLayerWithAlpha() returned error: %v", err)
+	}
+	out, err := layer.Forward(inputs)
+	if err != nil {
+		t.Fatalf("Forward() returned error: %v", err)
+	}
+	if !almostEqual(out.At(0, 0), -0.6, 1e-12) {
+		t.Errorf("LeakyReLU layer(-2) = %v; want -0.6", out.At(0, 0))
+	}
+
+	if _, err := NewActivationLayerWithAlpha(ReLU, 0.3); err == nil {
+		t.Error("NewActivationLayerWithAlpha(ReLU) expected error, got nil")
+	}
+	if _, err := NewActivationLayerWithAlpha(ELU, -1); err == nil {
+		t.Error("NewActivationLayerWithAlpha(ELU, -1) expected error, got nil")
+	}
+	if _, err := NewActivationLayerWithAlpha(LeakyReLU, math.NaN()); err == nil {
+		t.Error("NewActivationLayerWithAlpha(LeakyReLU, NaN) expected error, got nil")
+	}
+
+	if got := NewActivationLayer(LeakyReLU).Alpha; got != DefaultLeakyReLUAlpha {
+		t.Errorf("NewActivationLayer(LeakyReLU).Alpha = %v; want %v", got, DefaultLeakyReLUAlpha)
+	}
+	if got := NewActivationLayer(ELU).Alpha; got != DefaultELUAlpha {
+		t.Errorf("NewActivationLayer(ELU).Alpha = %v; want %v", got, DefaultELUAlpha)
+	}
+	zero, err := NewActivationLayerWithAlpha(LeakyReLU, 0)
+	if err != nil {
+		t.Fatalf("NewActivationLayerWithAlpha(LeakyReLU, 0) returned error: %v", err)
+	}
+	if out, err = zero.Forward(inputs); err != nil {
+		t.Fatalf("Forward() returned error: %v", err)
+	}
+	if out.At(0, 0) != 0 {
+		t.Errorf("LeakyReLU layer with alpha 0 (-2) = %v; want 0", out.At(0, 0))
+	}
+}
+
+func TestSparsemax(t *testing.T) {
+	af := NewActivationFn()
+	out, err := af.Sparsemax(tensor(t, [][]float64{{1.0, 0.8, -1.0}, {2.0, 0.0, 0.0}}))
+	if err != nil {
+		t.Fatalf("Sparsemax() returned error: %v", err)
+	}
+	got := out.Rows()
+	want := [][]float64{{0.6, 0.4, 0}, {1, 0, 0}}
+	for i := range want {
+		for j := range want[i] {
+			if !almostEqual(got[i][j], want[i][j], 1e-12) {
+				t.Errorf("Sparsemax()[%d][%d] = %v; want %v", i, j, got[i][j], want[i][j])
+			}
+		}
+	}
+}
+
+func TestMaskedSoftmax(t *testing.T) {
+	af := NewActivationFn()
+	inputs := tensor(t, [][]float64{{1, 2, 3}, {1, 2, 3}})
+	mask := [][]bool{{true, true, false}, {false, false, false}}
+
+	out, err := af.MaskedSoftmax(inputs, mask)
+	if err != nil {
+		t.Fatalf("MaskedSoftmax() returned error: %v", err)
+	}
+	got := out.Rows()
+	softmax, _ := af.Softmax(tensor(t, [][]float64{{1, 2}}))
+	want := softmax.Rows()
+	if !almostEqual(got[0][0], want[0][0], 1e-12) || !almostEqual(got[0][1], want[0][1], 1e-12) || got[0][2] != 0 {
+		t.Errorf("MaskedSoftmax() row 0 = %v; want %v with masked position 0", got[0], want[0])
+	}
+	for j, v := range got[1] {
+		if v != 0 {
+			t.Errorf("MaskedSoftmax() fully masked row [1][%d] = %v; want 0", j, v)
+		}
+	}
+
+	dInputs := af.MaskedSoftmaxBackward(tensor(t, [][]float64{{1, -1, 5}, {1, 1, 1}}), out)
+	if dInputs.At(0, 2) != 0 {
+		t.Errorf("MaskedSoftmaxBackward() masked position gradient = %v; want 0", dInputs.At(0, 2))
+	}
+
+	if _, err := af.MaskedSoftmax(inputs, mask[:1]); err == nil {
+		t.Error("MaskedSoftmax() expected error on mask shape mismatch, got nil")
+	}
+}
+
+func almostEqual(a, b, tol float64) bool {
+	return math.Abs(a-b) <= tol
+}
+
+// tensor converts a test matrix into a *mathx.Tensor.
+func tensor(t *testing.T, m [][]float64) *mathx.Tensor {
+	t.Helper()
+	x, err := mathx.FromMatrix(m)
+	if err != nil {
+		t.Fatalf("FromMatrix() returned error: %v", err)
+	}
+	return x
+}
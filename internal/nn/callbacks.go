@@ -0,0 +1,161 @@
+package nn
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// EarlyStopping stops training once the monitored metric stops improving and
+// can restore the parameters from the best epoch when training ends.
+//
+// Metrics whose name ends in "accuracy" are maximized; all others are minimized.
+type EarlyStopping struct {
+	Monitor            string
+	Patience           int
+	MinDelta           float64
+	RestoreBestWeights bool
+
+	maximize     bool
+	best         float64
+	bestEpoch    int
+	wait         int
+	bestParams   [][]float64
+	stoppedEpoch int
+}
+
+// NewEarlyStopping creates an early-stopping callback watching monitor
+// (e.g. "val_loss"). Training stops after patience epochs without an
+// improvement larger than minDelta.
+func NewEarlyStopping(monitor string, patience int, minDelta float64, restoreBestWeights bool) (*EarlyStopping, error) {
+	if monitor == "" {
+		return nil, fmt.Errorf("monitor metric must be set")
+	}
+	if patience < 0 {
+		return nil, fmt.Errorf("patience must be non-negative, got %d", patience)
+	}
+	if minDelta < 0 {
+		return nil, fmt.Errorf("min delta must be non-negative, got %v", minDelta)
+	}
+
+	es := &EarlyStopping{
+		Monitor:            monitor,
+		Patience:           patience,
+		MinDelta:           minDelta,
+		RestoreBestWeights: restoreBestWeights,
+		maximize:           strings.HasSuffix(monitor, "accuracy"),
+	}
+	es.reset()
+	return es, nil
+}
+
+// reset initializes the tracking state.
+func (es *EarlyStopping) reset() {
+	es.best = math.Inf(1)
+	if es.maximize {
+		es.best = math.Inf(-1)
+	}
+	es.bestEpoch = -1
+	es.wait = 0
+	es.bestParams = nil
+	es.stoppedEpoch = -1
+}
+
+// Best returns the best value seen for the monitored metric and its epoch
+// (-1 if no epoch has been recorded yet).
+func (es *EarlyStopping) Best() (float64, int) { return es.best, es.bestEpoch }
+
+// StoppedEpoch returns the epoch at which training was stopped, or -1.
+func (es *EarlyStopping) StoppedEpoch() int { return es.stoppedEpoch }
+
+// OnBatchEnd does nothing; early stopping works on epoch metrics.
+func (es *EarlyStopping) OnBatchEnd(t *Trainer, batch int, logs Logs) error { return nil }
+
+// OnEpochEnd records improvements and stops the trainer when patience runs out.
+func (es *EarlyStopping) OnEpochEnd(t *Trainer, epoch int, logs Logs) error {
+	value, ok := logs[es.Monitor]
+	if !ok {
+		return fmt.Errorf("early stopping: metric %q not found in logs", es.Monitor)
+	}
+
+	if es.improved(value) {
+		es.best = value
+		es.bestEpoch = epoch
+		es.wait = 0
+		if es.RestoreBestWeights {
+			es.bestParams = CloneParams(t.Model.Params())
+		}
+		return nil
+	}
+
+	es.wait++
+	if es.wait >= es.Patience {
+		es.stoppedEpoch = epoch
+		t.Stop()
+	}
+	return nil
+}
+
+// OnTrainEnd restores the best parameters if requested.
+func (es *EarlyStopping) OnTrainEnd(t *Trainer, logs Logs) error {
+	if !es.RestoreBestWeights || es.bestParams == nil {
+		return nil
+	}
+	if err := CopyParams(t.Model.Params(), es.bestParams); err != nil {
+		return fmt.Errorf("early stopping: restoring best weights: %w", err)
+	}
+	return nil
+}
+
+// earlyStoppingState is the gob-encoded progress of an EarlyStopping.
+type earlyStoppingState struct {
+	Monitor      string
+	Best         float64
+	BestEpoch    int
+	Wait         int
+	BestParams   [][]float64
+	StoppedEpoch int
+}
+
+// State encodes the best value, the epochs waited so far and the best
+// parameters, so a resumed run stops and restores exactly like an
+// uninterrupted one.
+func (es *EarlyStopping) State() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(earlyStoppingState{
+		Monitor:      es.Monitor,
+		Best:         es.best,
+		BestEpoch:    es.bestEpoch,
+		Wait:         es.wait,
+		BestParams:   es.bestParams,
+		StoppedEpoch: es.stoppedEpoch,
+	})
+	return buf.Bytes(), err
+}
+
+// SetState restores progress saved by State for the same monitored metric.
+func (es *EarlyStopping) SetState(state []byte) error {
+	var s earlyStoppingState
+	if err := gob.NewDecoder(bytes.NewReader(state)).Decode(&s); err != nil {
+		return fmt.Errorf("early stopping: decoding state: %w", err)
+	}
+	if s.Monitor != es.Monitor {
+		return fmt.Errorf("early stopping: state monitors %q, callback monitors %q", s.Monitor, es.Monitor)
+	}
+	es.best = s.Best
+	es.bestEpoch = s.BestEpoch
+	es.wait = s.Wait
+	es.bestParams = s.BestParams
+	es.stoppedEpoch = s.StoppedEpoch
+	return nil
+}
+
+// improved reports whether value beats the best so far by more than MinDelta.
+func (es *EarlyStopping) improved(value float64) bool {
+	if es.maximize {
+		return value > es.best+es.MinDelta
+	}
+	return value < es.best-es.MinDelta
+}
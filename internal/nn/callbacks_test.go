@@ -0,0 +1,86 @@
+package nn
+
+import "testing"
+
+// scriptedEpochs feeds metric values to es as the epochs of a trainer whose
+// every parameter equals the epoch index, until es stops the trainer. It
+// returns the trainer after OnTrainEnd.
+func scriptedEpochs(t *testing.T, es *EarlyStopping, values []float64) *Trainer {
+	t.Helper()
+	layer, err := NewDenseLayer(2, 2)
+	if err != nil {
+		t.Fatalf("NewDenseLayer() returned error: %v", err)
+	}
+	trainer := &Trainer{Model: NewSequential(layer)}
+
+	var last Logs
+	for epoch, v := range values {
+		for _, p := range trainer.Model.Params() {
+			for j := range p {
+				p[j] = float64(epoch)
+			}
+		}
+		last = Logs{es.Monitor: v}
+		if err := es.OnEpochEnd(trainer, epoch, last); err != nil {
+			t.Fatalf("OnEpochEnd() returned error: %v", err)
+		}
+		if trainer.stopped {
+			break
+		}
+	}
+	if err := es.OnTrainEnd(trainer, last); err != nil {
+		t.Fatalf("OnTrainEnd() returned error: %v", err)
+	}
+	return trainer
+}
+
+func TestEarlyStopping(t *testing.T) {
+	tests := []struct {
+		name        string
+		monitor     string
+		patience    int
+		minDelta    float64
+		values      []float64
+		wantStopped int
+		wantBest    int
+	}{
+		{"stops after patience epochs", "val_loss", 2, 0, []float64{1, 0.8, 0.9, 0.85, 0.7}, 3, 1},
+		{"patience 0 stops on first miss", "val_loss", 0, 0, []float64{1, 0.9, 0.95, 0.5}, 2, 1},
+		{"improvement resets the wait", "val_loss", 2, 0, []float64{1, 1.1, 0.9, 1.1, 1.2, 0.1}, 4, 2},
+		{"min delta ignores small gains", "val_loss", 1, 0.1, []float64{1, 0.95, 0.5}, 1, 0},
+		{"accuracy is maximized", "val_accuracy", 1, 0, []float64{0.5, 0.7, 0.6, 0.9}, 2, 1},
+		{"never stops while improving", "val_loss", 1, 0, []float64{3, 2, 1}, -1, 2},
+	}
+
+	for _, tt := range tests {
+		es, err := NewEarlyStopping(tt.monitor, tt.patience, tt.minDelta, true)
+		if err != nil {
+			t.Fatalf("NewEarlyStopping() returned error: %v", err)
+		}
+		trainer := scriptedEpochs(t, es, tt.values)
+
+		if got := es.StoppedEpoch(); got != tt.wantStopped {
+			t.Errorf("%s: StoppedEpoch() = %d; want %d", tt.name, got, tt.wantStopped)
+		}
+		if _, epoch := es.Best(); epoch != tt.wantBest {
+			t.Errorf("%s: best epoch = %d; want %d", tt.name, epoch, tt.wantBest)
+		}
+		for i, p := range trainer.Model.Params() {
+			for j, v := range p {
+				if v != float64(tt.wantBest) {
+					t.Fatalf("%s: restored param[%d][%d] = %v; want the epoch %d weights", tt.name, i, j, v, tt.wantBest)
+				}
+			}
+		}
+	}
+}
+
+func TestEarlyStoppingMissingMetric(t *testing.T) {
+	es, err := NewEarlyStopping("val_loss", 1, 0, false)
+	if err != nil {
+		t.Fatalf("NewEarlyStopping() returned error: %v", err)
+	}
+	if err := es.OnEpochEnd(&Trainer{}, 0, Logs{"loss": 1}); err == nil {
+		t.Error("OnEpochEnd() expected error on missing metric, got nil")
+	}
+}
@@ -0,0 +1,359 @@
+package nn
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/SobhanYasami/nn-go/internal/rng"
+	"github.com/SobhanYasami/nn-go/internal/scheduler"
+)
+
+// Checkpoint files are named checkpoint-<epoch>.ckpt inside a checkpoint
+// directory and hold everything needed to continue a Trainer exactly where it
+// stopped: model parameters (in the binary model format), optimizer buffers,
+// scheduler progress, epoch/step counters, the state of callbacks such as
+// EarlyStopping and the RNG state of shuffling and of layers such as
+// DropoutLayer.
+const (
+	checkpointVersion = 1
+	checkpointPrefix  = "checkpoint-"
+	checkpointExt     = ".ckpt"
+)
+
+// checkpointData is the gob-encoded content of a checkpoint file.
+type checkpointData struct {
+	Version   int
+	Epoch     int
+	Step      int
+	Seed      uint64
+	RNG       []byte
+	LayerRNG  map[int][]byte // keyed by layer index
+	Model     []byte
+	Optimizer *OptimizerState
+	Scheduler scheduler.State
+	Callbacks map[int][]byte // keyed by callback index
+}
+
+// SaveCheckpoint writes the full training state to w.
+func (t *Trainer) SaveCheckpoint(w io.Writer) error {
+	if t.RNG == nil {
+		t.RNG = rng.New(t.Seed)
+	}
+	rngState, err := t.RNG.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("saving RNG state: %w", err)
+	}
+	layerRNG := map[int][]byte{}
+	for i, layer := range t.Model.Layers {
+		if l, ok := layer.(randomLayer); ok {
+			state, err := l.randSource().MarshalBinary()
+			if err != nil {
+				return fmt.Errorf("saving layer %d RNG state: %w", i, err)
+			}
+			layerRNG[i] = state
+		}
+	}
+
+	callbacks := map[int][]byte{}
+	for i, cb := range t.Callbacks {
+		if c, ok := cb.(StatefulCallback); ok {
+			state, err := c.State()
+			if err != nil {
+				return fmt.Errorf("saving callback %d state: %w", i, err)
+			}
+			callbacks[i] = state
+		}
+	}
+
+	var model bytes.Buffer
+	if err := t.Model.Save(&model); err != nil {
+		return fmt.Errorf("saving model: %w", err)
+	}
+
+	data := checkpointData{
+		Version:   checkpointVersion,
+		Epoch:     t.epoch,
+		Step:      t.step,
+		Seed:      t.RNG.Seed(),
+		RNG:       rngState,
+		LayerRNG:  layerRNG,
+		Model:     model.Bytes(),
+		Callbacks: callbacks,
+	}
+	if opt, ok := t.Optimizer.(StatefulOptimizer); ok {
+		state := opt.State()
+		data.Optimizer = &state
+	}
+	if sched, ok := t.Scheduler.(scheduler.Stateful); ok {
+		data.Scheduler = sched.State()
+	}
+
+	return gob.NewEncoder(w).Encode(data)
+}
+
+// LoadCheckpoint restores the training state saved by SaveCheckpoint. The
+// trainer must already hold a model with the same architecture,
+// optimizer/scheduler of the same types and its stateful callbacks at the
+// same positions as when the checkpoint was written.
+func (t *Trainer) LoadCheckpoint(r io.Reader) error {
+	var data checkpointData
+	if err := gob.NewDecoder(r).Decode(&data); err != nil {
+		return fmt.Errorf("decoding checkpoint: %w", err)
+	}
+	if data.Version != checkpointVersion {
+		return fmt.Errorf("unsupported checkpoint version %d", data.Version)
+	}
+
+	saved, err := Load(bytes.NewReader(data.Model))
+	if err != nil {
+		return fmt.Errorf("loading model: %w", err)
+	}
+	if err := t.checkArchitecture(saved); err != nil {
+		return err
+	}
+	params := t.Model.Params()
+	if err := validateParamsGrads(params, saved.Params()); err != nil {
+		return fmt.Errorf("checkpoint does not match model: %w", err)
+	}
+
+	shuffle := rng.New(data.Seed)
+	if err := shuffle.UnmarshalBinary(data.RNG); err != nil {
+		return fmt.Errorf("restoring RNG state: %w", err)
+	}
+	layerRNG := make(map[int]*rng.RNG, len(data.LayerRNG))
+	for i, state := range data.LayerRNG {
+		if i < 0 || i >= len(t.Model.Layers) {
+			return fmt.Errorf("checkpoint has RNG state for missing layer %d", i)
+		}
+		if _, ok := t.Model.Layers[i].(randomLayer); !ok {
+			return fmt.Errorf("checkpoint has RNG state for layer %d but %T draws no random numbers", i, t.Model.Layers[i])
+		}
+		r := rng.New(0)
+		if err := r.UnmarshalBinary(state); err != nil {
+			return fmt.Errorf("restoring layer %d RNG state: %w", i, err)
+		}
+		layerRNG[i] = r
+	}
+
+	for i := range data.Callbacks {
+		if i < 0 || i >= len(t.Callbacks) {
+			return fmt.Errorf("checkpoint has state for missing callback %d", i)
+		}
+		if _, ok := t.Callbacks[i].(StatefulCallback); !ok {
+			return fmt.Errorf("checkpoint has state for callback %d but %T cannot restore it", i, t.Callbacks[i])
+		}
+	}
+
+	if data.Optimizer != nil {
+		opt, ok := t.Optimizer.(StatefulOptimizer)
+		if !ok {
+			return fmt.Errorf("checkpoint has %q optimizer state but %T cannot restore it", data.Optimizer.Name, t.Optimizer)
+		}
+		if err := opt.LoadState(*data.Optimizer); err != nil {
+			return fmt.Errorf("restoring optimizer: %w", err)
+		}
+	}
+	if data.Scheduler != nil {
+		sched, ok := t.Scheduler.(scheduler.Stateful)
+		if !ok {
+			return errors.New("checkpoint has scheduler state but the trainer's scheduler cannot restore it")
+		}
+		if err := sched.LoadState(data.Scheduler); err != nil {
+			return fmt.Errorf("restoring scheduler: %w", err)
+		}
+	}
+	for i, state := range data.Callbacks {
+		if err := t.Callbacks[i].(StatefulCallback).SetState(state); err != nil {
+			return fmt.Errorf("restoring callback %d: %w", i, err)
+		}
+	}
+
+	if err := CopyParams(params, saved.Params()); err != nil {
+		return fmt.Errorf("restoring model: %w", err)
+	}
+	t.epoch = data.Epoch
+	t.step = data.Step
+	for i, r := range layerRNG {
+		t.Model.Layers[i].(randomLayer).setRandSource(r)
+	}
+	t.Seed = data.Seed
+	t.RNG = shuffle
+	return nil
+}
+
+// Resume loads the most recent checkpoint in dir. It reports false, and
+// leaves the trainer untouched, when the directory holds no checkpoint.
+func (t *Trainer) Resume(dir string) (bool, error) {
+	path, err := LatestCheckpoint(dir)
+	if err != nil || path == "" {
+		return false, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	if err := t.LoadCheckpoint(f); err != nil {
+		return false, fmt.Errorf("%s: %w", path, err)
+	}
+	return true, nil
+}
+
+// checkArchitecture verifies that saved has the same layers as the trainer's model.
+func (t *Trainer) checkArchitecture(saved *Sequential) error {
+	current, err := t.Model.layerSpecs()
+	if err != nil {
+		return err
+	}
+	restored, err := saved.layerSpecs()
+	if err != nil {
+		return err
+	}
+	if len(current) != len(restored) {
+		return fmt.Errorf("checkpoint has %d layers, model has %d", len(restored), len(current))
+	}
+	for i := range current {
+		if current[i].Type != restored[i].Type || current[i].Activation != restored[i].Activation {
+			return fmt.Errorf("layer %d: checkpoint has %s %s, model has %s %s",
+				i, restored[i].Type, restored[i].Activation, current[i].Type, current[i].Activation)
+		}
+	}
+	return nil
+}
+
+//? ------------------------------
+//? Checkpoint Directory
+//? ------------------------------
+
+// Checkpointer is a Callback that writes a checkpoint every Every epochs
+// into Dir and keeps only the KeepLast most recent ones (0 keeps all).
+//
+// Resuming works at epoch granularity: a checkpoint holds neither the batch
+// position nor the shuffle order of an unfinished epoch, so training always
+// continues from the start of the epoch after the last completed one. A
+// checkpoint saved mid-epoch with SaveCheckpoint would re-run that whole
+// epoch, with a new shuffle, on the partially trained weights.
+//
+// Callbacks run in registration order, so register the Checkpointer after
+// stateful callbacks such as EarlyStopping; otherwise its checkpoint misses
+// their update for the epoch just finished.
+type Checkpointer struct {
+	Dir      string
+	Every    int
+	KeepLast int
+}
+
+// NewCheckpointer creates dir if needed and returns a checkpointing callback.
+func NewCheckpointer(dir string, every, keepLast int) (*Checkpointer, error) {
+	if every <= 0 {
+		return nil, fmt.Errorf("checkpoint interval must be positive, got %d", every)
+	}
+	if keepLast < 0 {
+		return nil, fmt.Errorf("keepLast must be non-negative, got %d", keepLast)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Checkpointer{Dir: dir, Every: every, KeepLast: keepLast}, nil
+}
+
+// OnBatchEnd does nothing; checkpoints are only taken at epoch boundaries.
+func (c *Checkpointer) OnBatchEnd(t *Trainer, batch int, logs Logs) error { return nil }
+
+// OnEpochEnd writes a checkpoint when the completed epoch count is a multiple of Every.
+func (c *Checkpointer) OnEpochEnd(t *Trainer, epoch int, logs Logs) error {
+	if t.Epoch()%c.Every != 0 {
+		return nil
+	}
+	_, err := c.Save(t)
+	return err
+}
+
+// OnTrainEnd does nothing.
+func (c *Checkpointer) OnTrainEnd(t *Trainer, logs Logs) error { return nil }
+
+// Save writes a checkpoint for the trainer's current epoch, rotates old
+// checkpoints and returns the new file's path.
+func (c *Checkpointer) Save(t *Trainer) (string, error) {
+	path := filepath.Join(c.Dir, fmt.Sprintf("%s%08d%s", checkpointPrefix, t.Epoch(), checkpointExt))
+
+	// Write to a temporary file first so a crash never leaves a truncated checkpoint.
+	tmp, err := os.CreateTemp(c.Dir, checkpointPrefix+"*.tmp")
+	if err != nil {
+		return "", err
+	}
+	if err := t.SaveCheckpoint(tmp); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+
+	return path, c.rotate()
+}
+
+// rotate removes all but the KeepLast newest checkpoints.
+func (c *Checkpointer) rotate() error {
+	if c.KeepLast == 0 {
+		return nil
+	}
+	paths, err := listCheckpoints(c.Dir)
+	if err != nil {
+		return err
+	}
+	for len(paths) > c.KeepLast {
+		if err := os.Remove(paths[0]); err != nil {
+			return err
+		}
+		paths = paths[1:]
+	}
+	return nil
+}
+
+// LatestCheckpoint returns the newest checkpoint in dir, or "" if there is none.
+func LatestCheckpoint(dir string) (string, error) {
+	paths, err := listCheckpoints(dir)
+	if err != nil || len(paths) == 0 {
+		return "", err
+	}
+	return paths[len(paths)-1], nil
+}
+
+// listCheckpoints returns the checkpoint files in dir, oldest first.
+// A missing directory is treated as empty.
+func listCheckpoints(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() && strings.HasPrefix(name, checkpointPrefix) && strings.HasSuffix(name, checkpointExt) {
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+	// Epochs are zero-padded, so lexical order is chronological.
+	sort.Strings(paths)
+	return paths, nil
+}
@@ -0,0 +1,121 @@
+package nn
+
+import (
+	"bytes"
+	"encoding/gob"
+	"strings"
+	"testing"
+
+	dataset "github.com/SobhanYasami/nn-go/internal/data"
+	"github.com/SobhanYasami/nn-go/internal/rng"
+	"github.com/SobhanYasami/nn-go/internal/scheduler"
+)
+
+// resumableTrainer extends newTestTrainer with validation data, a learning
+// rate schedule and early stopping that restores the best weights, followed
+// by a Checkpointer writing to dir after every epoch.
+func resumableTrainer(t *testing.T, dir string) (*Trainer, *EarlyStopping) {
+	t.Helper()
+	trainer := newTestTrainer(t, 11)
+
+	X, y := dataset.CreateDataWithRNG(20, 3, rng.New(11).Stream("validation"))
+	trainer.Validation = &Dataset{X: tensor(t, X), Y: y}
+	sched, err := scheduler.NewCosineWarmRestarts(0.05, 0.001, 4, 2)
+	if err != nil {
+		t.Fatalf("NewCosineWarmRestarts() returned error: %v", err)
+	}
+	trainer.Scheduler = sched
+
+	earlyStopping, err := NewEarlyStopping("val_loss", 4, 0, true)
+	if err != nil {
+		t.Fatalf("NewEarlyStopping() returned error: %v", err)
+	}
+	checkpointer, err := NewCheckpointer(dir, 1, 0)
+	if err != nil {
+		t.Fatalf("NewCheckpointer() returned error: %v", err)
+	}
+	trainer.AddCallback(earlyStopping, checkpointer)
+	return trainer, earlyStopping
+}
+
+func TestResumeMatchesUninterruptedRun(t *testing.T) {
+	// The straight run peaks at epoch 6 and stops at epoch 10, so the
+	// interruption falls between its best epoch and the end of its patience.
+	const epochs, interrupted = 30, 8
+
+	straight, straightES := resumableTrainer(t, t.TempDir())
+	want, err := straight.Fit(epochs)
+	if err != nil {
+		t.Fatalf("Fit() returned error: %v", err)
+	}
+	if len(want) <= interrupted || straightES.StoppedEpoch() < 0 {
+		t.Fatalf("test setup: run should stop early after epoch %d, ran %d epochs and stopped at %d",
+			interrupted, len(want), straightES.StoppedEpoch())
+	}
+
+	// Train part of the way, then continue from the last checkpoint with a
+	// fresh trainer, as after the process was killed.
+	dir := t.TempDir()
+	first, _ := resumableTrainer(t, dir)
+	if _, err := first.Fit(interrupted); err != nil {
+		t.Fatalf("Fit() returned error: %v", err)
+	}
+	resumed, resumedES := resumableTrainer(t, dir)
+	ok, err := resumed.Resume(dir)
+	if err != nil || !ok {
+		t.Fatalf("Resume() = %v, %v; want true, nil", ok, err)
+	}
+	got, err := resumed.Fit(epochs)
+	if err != nil {
+		t.Fatalf("Fit() returned error: %v", err)
+	}
+
+	if len(got) != len(want)-interrupted {
+		t.Fatalf("resumed run trained %d epochs; want %d", len(got), len(want)-interrupted)
+	}
+	for i, logs := range got {
+		for _, key := range []string{"loss", "val_loss", "lr"} {
+			if logs[key] != want[interrupted+i][key] {
+				t.Errorf("epoch %d %s = %v after resuming; want %v", interrupted+i, key, logs[key], want[interrupted+i][key])
+			}
+		}
+	}
+	if resumedES.StoppedEpoch() != straightES.StoppedEpoch() {
+		t.Errorf("resumed run stopped at epoch %d; want %d", resumedES.StoppedEpoch(), straightES.StoppedEpoch())
+	}
+	wantParams, gotParams := straight.Model.Params(), resumed.Model.Params()
+	for i := range wantParams {
+		for j := range wantParams[i] {
+			if gotParams[i][j] != wantParams[i][j] {
+				t.Fatalf("param[%d][%d] = %v after resuming; want %v", i, j, gotParams[i][j], wantParams[i][j])
+			}
+		}
+	}
+}
+
+func TestLoadCheckpointRejectsMissingCallback(t *testing.T) {
+	dir := t.TempDir()
+	trainer, _ := resumableTrainer(t, dir)
+	if _, err := trainer.Fit(1); err != nil {
+		t.Fatalf("Fit() returned error: %v", err)
+	}
+
+	// Without the EarlyStopping callback its saved state has nowhere to go.
+	fresh := newTestTrainer(t, 11)
+	if _, err := fresh.Resume(dir); err == nil {
+		t.Error("Resume() expected error for callback state without a callback, got nil")
+	}
+}
+
+func TestLoadCheckpointRejectsOtherVersion(t *testing.T) {
+	for _, version := range []int{0, checkpointVersion + 1} {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(checkpointData{Version: version}); err != nil {
+			t.Fatalf("Encode() returned error: %v", err)
+		}
+		err := newTestTrainer(t, 11).LoadCheckpoint(&buf)
+		if err == nil || !strings.Contains(err.Error(), "unsupported checkpoint version") {
+			t.Errorf("LoadCheckpoint(version %d) error = %v; want unsupported version", version, err)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package nn
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"github.com/SobhanYasami/nn-go/internal/mathx"
+)
+
+// Initializer fills a weight matrix in place. Rows are output neurons and
+// columns are inputs, so fanIn = weights.Dim(1) and fanOut = weights.Dim(0).
+type Initializer func(weights *mathx.Tensor, rng *rand.Rand)
+
+// DenseOption configures NewDenseLayer.
+type DenseOption func(*denseConfig)
+
+// denseConfig holds the initialization settings of a DenseLayer.
+type denseConfig struct {
+	weightInit Initializer
+	biasInit   float64
+	rng        *rand.Rand
+}
+
+// WithWeightInit sets the weight initializer (default RandomNormal(0, 0.01)).
+func WithWeightInit(init Initializer) DenseOption {
+	return func(c *denseConfig) { c.weightInit = init }
+}
+
+// WithBiasInit sets every bias to value (default 0).
+func WithBiasInit(value float64) DenseOption {
+	return func(c *denseConfig) { c.biasInit = value }
+}
+
+// WithRand draws the initial weights from rng instead of a time-seeded
+// source, typically a stream of a shared root: WithRand(root.Stream("dense0").Rand).
+func WithRand(rng *rand.Rand) DenseOption {
+	return func(c *denseConfig) { c.rng = rng }
+}
+
+//? ------------------------------
+//? Variance-Scaling Initializers
+//? ------------------------------
+
+// GlorotUniform (Xavier) draws from U(-l, l) with l = √(6 / (fanIn + fanOut)).
+// Suited to tanh, sigmoid and softmax layers.
+func GlorotUniform() Initializer {
+	return func(weights *mathx.Tensor, rng *rand.Rand) {
+		fanIn, fanOut := fans(weights)
+		fillUniform(weights, rng, math.Sqrt(6/float64(fanIn+fanOut)))
+	}
+}
+
+// GlorotNormal (Xavier) draws from N(0, σ²) with σ = √(2 / (fanIn + fanOut)).
+func GlorotNormal() Initializer {
+	return func(weights *mathx.Tensor, rng *rand.Rand) {
+		fanIn, fanOut := fans(weights)
+		fillNormal(weights, rng, 0, math.Sqrt(2/float64(fanIn+fanOut)))
+	}
+}
+
+// HeUniform (Kaiming) draws from U(-l, l) with l = √(6 / fanIn).
+// Suited to ReLU-family layers.
+func HeUniform() Initializer {
+	return func(weights *mathx.Tensor, rng *rand.Rand) {
+		fanIn, _ := fans(weights)
+		fillUniform(weights, rng, math.Sqrt(6/float64(fanIn)))
+	}
+}
+
+// HeNormal (Kaiming) draws from N(0, σ²) with σ = √(2 / fanIn).
+func HeNormal() Initializer {
+	return func(weights *mathx.Tensor, rng *rand.Rand) {
+		fanIn, _ := fans(weights)
+		fillNormal(weights, rng, 0, math.Sqrt(2/float64(fanIn)))
+	}
+}
+
+// LeCunUniform draws from U(-l, l) with l = √(3 / fanIn).
+func LeCunUniform() Initializer {
+	return func(weights *mathx.Tensor, rng *rand.Rand) {
+		fanIn, _ := fans(weights)
+		fillUniform(weights, rng, math.Sqrt(3/float64(fanIn)))
+	}
+}
+
+// LeCunNormal draws from N(0, σ²) with σ = √(1 / fanIn). Pair it with SELU.
+func LeCunNormal() Initializer {
+	return func(weights *mathx.Tensor, rng *rand.Rand) {
+		fanIn, _ := fans(weights)
+		fillNormal(weights, rng, 0, math.Sqrt(1/float64(fanIn)))
+	}
+}
+
+//? ------------------------------
+//? Other Initializers
+//? ------------------------------
+
+// RandomNormal draws from N(mean, std²).
+func RandomNormal(mean, std float64) Initializer {
+	return func(weights *mathx.Tensor, rng *rand.Rand) {
+		fillNormal(weights, rng, mean, std)
+	}
+}
+
+// TruncatedNormal draws from N(mean, std²), redrawing any value more than two
+// standard deviations from the mean.
+func TruncatedNormal(mean, std float64) Initializer {
+	return func(weights *mathx.Tensor, rng *rand.Rand) {
+		weights.Apply(func(float64) float64 {
+			z := rng.NormFloat64()
+			for math.Abs(z) > 2 {
+				z = rng.NormFloat64()
+			}
+			return mean + std*z
+		})
+	}
+}
+
+// Constant sets every weight to value.
+func Constant(value float64) Initializer {
+	return func(weights *mathx.Tensor, _ *rand.Rand) {
+		weights.Fill(value)
+	}
+}
+
+// Orthogonal fills the weights with a (semi-)orthogonal matrix scaled by
+// gain: rows are orthonormal when there are fewer outputs than inputs,
+// columns otherwise. It orthonormalizes a Gaussian matrix with modified
+// Gram–Schmidt, which preserves gradient norms in deep stacks.
+func Orthogonal(gain float64) Initializer {
+	return func(weights *mathx.Tensor, rng *rand.Rand) {
+		rows, cols := weights.Dim(0), weights.Dim(1)
+		// Orthonormalize the shorter dimension as vectors of the longer one.
+		n, dim := min(rows, cols), max(rows, cols)
+
+		vectors := make([][]float64, n)
+		for k := range vectors {
+			vectors[k] = make([]float64, dim)
+			for {
+				for d := range vectors[k] {
+					vectors[k][d] = rng.NormFloat64()
+				}
+				for _, prev := range vectors[:k] {
+					proj := dot(vectors[k], prev)
+					for d := range vectors[k] {
+						vectors[k][d] -= proj * prev[d]
+					}
+				}
+				// Redraw in the (practically impossible) case of a degenerate vector.
+				if norm := math.Sqrt(dot(vectors[k], vectors[k])); norm > 1e-10 {
+					for d := range vectors[k] {
+						vectors[k][d] /= norm
+					}
+					break
+				}
+			}
+		}
+
+		for i := 0; i < rows; i++ {
+			for j := 0; j < cols; j++ {
+				if rows <= cols {
+					weights.Set(gain*vectors[i][j], i, j)
+				} else {
+					weights.Set(gain*vectors[j][i], i, j)
+				}
+			}
+		}
+	}
+}
+
+// fans returns the fan-in and fan-out of a weight matrix.
+func fans(weights *mathx.Tensor) (int, int) {
+	return weights.Dim(1), weights.Dim(0)
+}
+
+// fillUniform draws every weight from U(-limit, limit).
+func fillUniform(weights *mathx.Tensor, rng *rand.Rand, limit float64) {
+	weights.Apply(func(float64) float64 {
+		return (2*rng.Float64() - 1) * limit
+	})
+}
+
+// fillNormal draws every weight from N(mean, std²).
+func fillNormal(weights *mathx.Tensor, rng *rand.Rand, mean, std float64) {
+	weights.Apply(func(float64) float64 {
+		return mean + std*rng.NormFloat64()
+	})
+}
@@ -0,0 +1,138 @@
+package nn
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/SobhanYasami/nn-go/internal/mathx"
+)
+
+// initialized fills a rows×cols matrix with init from a fixed seed.
+func initialized(init Initializer, rows, cols int) *mathx.Tensor {
+	weights := mathx.NewTensor(rows, cols)
+	init(weights, rand.New(rand.NewPCG(1, 2)))
+	return weights
+}
+
+func TestUniformInitializersStayInBounds(t *testing.T) {
+	const fanOut, fanIn = 50, 80
+	tests := []struct {
+		name  string
+		init  Initializer
+		limit float64
+	}{
+		{"glorot uniform", GlorotUniform(), math.Sqrt(6.0 / (fanIn + fanOut))},
+		{"he uniform", HeUniform(), math.Sqrt(6.0 / fanIn)},
+		{"lecun uniform", LeCunUniform(), math.Sqrt(3.0 / fanIn)},
+	}
+
+	for _, tt := range tests {
+		var maxAbs float64
+		for _, w := range initialized(tt.init, fanOut, fanIn).Data() {
+			maxAbs = math.Max(maxAbs, math.Abs(w))
+		}
+		if maxAbs > tt.limit {
+			t.Errorf("%s: max |w| = %v; want at most %v", tt.name, maxAbs, tt.limit)
+		}
+		// 4000 draws come close to the bound.
+		if maxAbs < 0.99*tt.limit {
+			t.Errorf("%s: max |w| = %v; want close to %v", tt.name, maxAbs, tt.limit)
+		}
+	}
+}
+
+func TestNormalInitializersMatchVariance(t *testing.T) {
+	const fanOut, fanIn = 50, 80
+	tests := []struct {
+		name     string
+		init     Initializer
+		mean     float64
+		variance float64
+	}{
+		{"glorot normal", GlorotNormal(), 0, 2.0 / (fanIn + fanOut)},
+		{"he normal", HeNormal(), 0, 2.0 / fanIn},
+		{"lecun normal", LeCunNormal(), 0, 1.0 / fanIn},
+		{"random normal", RandomNormal(0.5, 0.1), 0.5, 0.01},
+	}
+
+	for _, tt := range tests {
+		mean, variance := moments(initialized(tt.init, fanOut, fanIn).Data())
+		// The standard error of the mean is σ/√4000 ≈ 0.016σ.
+		if math.Abs(mean-tt.mean) > 0.05*math.Sqrt(tt.variance) {
+			t.Errorf("%s: mean = %v; want %v", tt.name, mean, tt.mean)
+		}
+		if math.Abs(variance/tt.variance-1) > 0.05 {
+			t.Errorf("%s: variance = %v; want %v", tt.name, variance, tt.variance)
+		}
+	}
+}
+
+func TestTruncatedNormalStaysWithinTwoStd(t *testing.T) {
+	const mean, std = 1.0, 0.2
+	values := initialized(TruncatedNormal(mean, std), 100, 100).Data()
+	for _, w := range values {
+		if math.Abs(w-mean) > 2*std {
+			t.Fatalf("TruncatedNormal drew %v; want within %v of %v", w, 2*std, mean)
+		}
+	}
+	// Truncating N(0, 1) at ±2 leaves a variance of about 0.774.
+	if _, variance := moments(values); math.Abs(variance/(0.774*std*std)-1) > 0.05 {
+		t.Errorf("TruncatedNormal variance = %v; want %v", variance, 0.774*std*std)
+	}
+}
+
+func TestConstantInitializer(t *testing.T) {
+	for _, w := range initialized(Constant(0.25), 3, 4).Data() {
+		if w != 0.25 {
+			t.Fatalf("Constant(0.25) weight = %v; want 0.25", w)
+		}
+	}
+}
+
+func TestOrthogonalInitializer(t *testing.T) {
+	const gain = 1.5
+	for _, shape := range [][2]int{{3, 7}, {7, 3}, {5, 5}} {
+		rows, cols := shape[0], shape[1]
+		w := initialized(Orthogonal(gain), rows, cols).Rows()
+
+		// Wide matrices have orthogonal rows (W·Wᵀ = gain²·I), tall ones
+		// orthogonal columns (Wᵀ·W = gain²·I).
+		n, dim := min(rows, cols), max(rows, cols)
+		vector := func(k, d int) float64 {
+			if rows <= cols {
+				return w[k][d]
+			}
+			return w[d][k]
+		}
+		for a := 0; a < n; a++ {
+			for b := 0; b < n; b++ {
+				var got float64
+				for d := 0; d < dim; d++ {
+					got += vector(a, d) * vector(b, d)
+				}
+				want := 0.0
+				if a == b {
+					want = gain * gain
+				}
+				if !almostEqual(got, want, 1e-12) {
+					t.Errorf("%d×%d: Gram[%d][%d] = %v; want %v", rows, cols, a, b, got, want)
+				}
+			}
+		}
+	}
+}
+
+// moments returns the sample mean and variance of values.
+func moments(values []float64) (float64, float64) {
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, variance / float64(len(values)-1)
+}
@@ -2,67 +2,107 @@ package nn
 
 import (
 	"errors"
-	"math/rand"
-	"time"
+	"fmt"
+	"math"
+
+	"github.com/SobhanYasami/nn-go/internal/mathx"
+	"github.com/SobhanYasami/nn-go/internal/rng"
 )
 
+// Layer is a single differentiable stage of a network.
+//
+// Forward and Backward exchange batches as tensors whose first axis is the
+// batch. Params and Grads expose the trainable parameters as a flat list of
+// vectors; the i-th gradient vector always has the same length as the i-th
+// parameter vector, so callers can update parameters without knowing the
+// layer type.
+type Layer interface {
+	// Forward computes the layer output for a batch and caches what Backward needs.
+	Forward(inputs *mathx.Tensor) (*mathx.Tensor, error)
+	// Backward receives dLoss/dOutput, stores parameter gradients and returns dLoss/dInput.
+	Backward(dOutputs *mathx.Tensor) (*mathx.Tensor, error)
+	// Params returns the trainable parameters (nil for stateless layers).
+	Params() [][]float64
+	// Grads returns the gradients aligned with Params.
+	Grads() [][]float64
+}
+
 // ? DenseLayer represents a fully connected layer.
+// Weights is nNeurons×nInputs, one row per neuron.
 type DenseLayer struct {
-	Weights [][]float64
+	Weights *mathx.Tensor
 	Biases  []float64
 
 	//! Cache for backpropagation
-	Input    [][]float64
-	Output   [][]float64
-	DWeights [][]float64
+	Input    *mathx.Tensor
+	Output   *mathx.Tensor
+	DWeights *mathx.Tensor
 	DBiases  []float64
 }
 
 // ? NewDenseLayer creates a new dense layer.
-func NewDenseLayer(nInputs, nNeurons int) (*DenseLayer, error) {
+// Weights default to N(0, 0.01²) and biases to 0; see WithWeightInit and WithBiasInit.
+func NewDenseLayer(nInputs, nNeurons int, opts ...DenseOption) (*DenseLayer, error) {
 	if nInputs <= 0 || nNeurons <= 0 {
 		return nil, errors.New("inputs and neurons must be positive")
 	}
 
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	weights := make([][]float64, nNeurons)
-	for i := range weights {
-		weights[i] = make([]float64, nInputs)
-		for j := range weights[i] {
-			weights[i][j] = rng.NormFloat64() * 0.01
-		}
+	cfg := denseConfig{weightInit: RandomNormal(0, 0.01)}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.weightInit == nil {
+		return nil, errors.New("weight initializer cannot be nil")
+	}
+	if cfg.rng == nil {
+		cfg.rng = rng.NewFromTime().Rand
 	}
 
+	weights := mathx.NewTensor(nNeurons, nInputs)
+	cfg.weightInit(weights, cfg.rng)
+
 	biases := make([]float64, nNeurons)
+	for i := range biases {
+		biases[i] = cfg.biasInit
+	}
 
 	return &DenseLayer{
-		Weights: weights,
-		Biases:  biases,
+		Weights:  weights,
+		Biases:   biases,
+		DWeights: mathx.NewTensor(nNeurons, nInputs),
+		DBiases:  make([]float64, nNeurons),
 	}, nil
 }
 
 // ?
 //
 //	Forward pass: store inputs and outputs for backprop.
+//	Output = X · Wᵀ + b
 //
 // ##
-func (dl *DenseLayer) Forward(X [][]float64) ([][]float64, error) {
-	if len(X) == 0 {
+func (dl *DenseLayer) Forward(X *mathx.Tensor) (*mathx.Tensor, error) {
+	if X == nil || X.Size() == 0 {
 		return nil, errors.New("empty input")
 	}
+	nInputs := dl.Weights.Dim(1)
+	if X.Dims() != 2 || X.Dim(1) != nInputs {
+		return nil, fmt.Errorf("input has shape %v, expected [batch %d]", X.Shape(), nInputs)
+	}
 
 	dl.Input = X
-	output := make([][]float64, len(X))
-	for i, sample := range X {
-		row := make([]float64, len(dl.Weights))
-		for n, weights := range dl.Weights {
-			sum := dl.Biases[n]
-			for j, w := range weights {
-				sum += sample[j] * w
-			}
-			row[n] = sum
-		}
-		output[i] = row
+	product := mathx.NewTensor(X.Dim(0), dl.Weights.Dim(0))
+	if err := mathx.Gemm(false, true, 1, X, dl.Weights, 0, product); err != nil {
+		return nil, err
+	}
+	// The bias vector broadcasts across the batch dimension.
+	biases, err := mathx.FromSlice(dl.Biases, len(dl.Biases))
+	if err != nil {
+		return nil, err
+	}
+	ng := mathx.NumGo{}
+	output, err := ng.Add(product, biases)
+	if err != nil {
+		return nil, err
 	}
 	dl.Output = output
 	return output, nil
@@ -70,54 +110,421 @@ func (dl *DenseLayer) Forward(X [][]float64) ([][]float64, error) {
 
 // ?
 // Backward pass: compute gradients.
+// Parameters are left untouched; an Optimizer applies DWeights and DBiases.
 // ##
-func (dl *DenseLayer) Backward(dOutputs [][]float64, learningRate float64) [][]float64 {
-	batchSize := float64(len(dl.Input))
-
-	// Initialize gradients
-	dl.DWeights = make([][]float64, len(dl.Weights))
-	dl.DBiases = make([]float64, len(dl.Biases))
-	for i := range dl.DWeights {
-		dl.DWeights[i] = make([]float64, len(dl.Weights[i]))
-	}
-
-	// Compute dWeights, dBiases
-	for i := 0; i < len(dl.Weights); i++ { // each neuron
-		for j := 0; j < len(dl.Weights[i]); j++ {
-			var grad float64
-			for k := 0; k < len(dl.Input); k++ { // batch
-				grad += dl.Input[k][j] * dOutputs[k][i]
-			}
-			dl.DWeights[i][j] = grad / batchSize
+func (dl *DenseLayer) Backward(dOutputs *mathx.Tensor) (*mathx.Tensor, error) {
+	if dl.Input == nil {
+		return nil, errors.New("backward called before forward")
+	}
+	batch, nNeurons := dl.Input.Dim(0), dl.Weights.Dim(0)
+	if dOutputs == nil || dOutputs.Dims() != 2 || dOutputs.Dim(0) != batch || dOutputs.Dim(1) != nNeurons {
+		var shape []int
+		if dOutputs != nil {
+			shape = dOutputs.Shape()
 		}
+		return nil, fmt.Errorf("gradient has shape %v, expected [%d %d]", shape, batch, nNeurons)
+	}
+
+	batchSize := float64(batch)
+
+	// Gradient buffers are allocated once and reused so optimizers can hold on to them
+	if dl.DWeights == nil || !mathx.SameShape(dl.DWeights, dl.Weights) || len(dl.DBiases) != len(dl.Biases) {
+		dl.DWeights = mathx.NewTensor(dl.Weights.Shape()...)
+		dl.DBiases = make([]float64, len(dl.Biases))
+	}
+
+	// dWeights = dOutputsᵀ · X / N
+	if err := mathx.Gemm(true, false, 1/batchSize, dOutputs, dl.Input, 0, dl.DWeights); err != nil {
+		return nil, err
+	}
+
+	// dBiases = column sums of dOutputs / N
+	ng := mathx.NumGo{}
+	sums, err := ng.Sum(dOutputs, false, 0)
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range sums.Data() {
+		dl.DBiases[i] = s / batchSize
+	}
+
+	// Compute gradient for inputs: dOutputs · W
+	dInputs := mathx.NewTensor(batch, dl.Weights.Dim(1))
+	if err := mathx.Gemm(false, false, 1, dOutputs, dl.Weights, 0, dInputs); err != nil {
+		return nil, err
+	}
+	return dInputs, nil
+}
+
+// Params returns the weight rows followed by the bias vector.
+// The returned slices alias the layer's storage.
+func (dl *DenseLayer) Params() [][]float64 {
+	return append(dl.Weights.Rows(), dl.Biases)
+}
+
+// Grads returns the weight-gradient rows followed by the bias gradient,
+// in the same order as Params.
+func (dl *DenseLayer) Grads() [][]float64 {
+	return append(dl.DWeights.Rows(), dl.DBiases)
+}
+
+// Clone returns a deep copy of the layer's parameters with fresh, zeroed
+// gradient buffers and no cached forward state.
+func (dl *DenseLayer) Clone() *DenseLayer {
+	return &DenseLayer{
+		Weights:  dl.Weights.Clone(),
+		Biases:   append([]float64(nil), dl.Biases...),
+		DWeights: mathx.NewTensor(dl.Weights.Shape()...),
+		DBiases:  make([]float64, len(dl.Biases)),
+	}
+}
+
+// ActivationLayer wraps an ActivationType so it can be stacked like any other Layer.
+type ActivationLayer struct {
+	Activation ActivationType
+	// Alpha is the slope of LeakyReLU or the alpha of ELU.
+	Alpha float64
+	// Temperature divides the logits of a Softmax layer; 0 selects 1.
+	Temperature float64
+
+	fn       *ActivationFn
+	backward func(dOutputs *mathx.Tensor) *mathx.Tensor
+}
+
+// NewActivationLayer creates a stateless layer applying the given activation.
+// LeakyReLU and ELU layers start with DefaultLeakyReLUAlpha and DefaultELUAlpha.
+func NewActivationLayer(activation ActivationType) *ActivationLayer {
+	layer := &ActivationLayer{
+		Activation: activation,
+		fn:         NewActivationFn(),
+	}
+	switch activation {
+	case LeakyReLU:
+		layer.Alpha = DefaultLeakyReLUAlpha
+	case ELU:
+		layer.Alpha = DefaultELUAlpha
+	}
+	return layer
+}
+
+// NewActivationLayerWithAlpha creates a LeakyReLU or ELU layer with a custom alpha.
+func NewActivationLayerWithAlpha(activation ActivationType, alpha float64) (*ActivationLayer, error) {
+	if err := validateAlpha(activation, alpha); err != nil {
+		return nil, err
 	}
+	layer := NewActivationLayer(activation)
+	layer.Alpha = alpha
+	return layer, nil
+}
+
+// validateAlpha checks that activation takes an alpha and that alpha is a
+// finite, non-negative number.
+func validateAlpha(activation ActivationType, alpha float64) error {
+	if activation != LeakyReLU && activation != ELU {
+		return fmt.Errorf("%s activation has no alpha parameter", activation)
+	}
+	if !(alpha >= 0) || math.IsInf(alpha, 0) {
+		return fmt.Errorf("alpha must be a non-negative number, got %v", alpha)
+	}
+	return nil
+}
 
-	for i := 0; i < len(dl.Biases); i++ {
-		var grad float64
-		for k := 0; k < len(dOutputs); k++ {
-			grad += dOutputs[k][i]
+// Forward applies the activation and remembers its gradient function.
+func (al *ActivationLayer) Forward(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	al.fn.LeakyReLUAlpha = al.Alpha
+	al.fn.ELUAlpha = al.Alpha
+	al.fn.SoftmaxTemperature = al.Temperature
+	result, err := al.fn.ApplyWithGrad(al.Activation, inputs)
+	if err != nil {
+		return nil, err
+	}
+	al.backward = result.Backward
+	return result.Output, nil
+}
+
+// Backward applies the activation derivative to dOutputs.
+func (al *ActivationLayer) Backward(dOutputs *mathx.Tensor) (*mathx.Tensor, error) {
+	if al.backward == nil {
+		return nil, errors.New("backward called before forward")
+	}
+	dInputs := al.backward(dOutputs)
+	if dInputs == nil {
+		return nil, fmt.Errorf("%s backward: gradient shape does not match forward output", al.Activation)
+	}
+	return dInputs, nil
+}
+
+// Params returns nil; activation layers are not trainable.
+func (al *ActivationLayer) Params() [][]float64 { return nil }
+
+// Grads returns nil; activation layers are not trainable.
+func (al *ActivationLayer) Grads() [][]float64 { return nil }
+
+//? ------------------------------
+//? Learnable Activation Layers
+//? ------------------------------
+
+// PReLULayer is a parametric ReLU with one learnable negative slope per
+// feature: f(x) = x if x > 0, else alpha_j * x.
+type PReLULayer struct {
+	Alpha []float64
+
+	//! Cache for backpropagation
+	Input  *mathx.Tensor
+	DAlpha []float64
+}
+
+// NewPReLULayer creates a PReLU layer for nFeatures inputs with every slope set to initAlpha.
+func NewPReLULayer(nFeatures int, initAlpha float64) (*PReLULayer, error) {
+	if nFeatures <= 0 {
+		return nil, errors.New("features must be positive")
+	}
+	alpha := make([]float64, nFeatures)
+	for j := range alpha {
+		alpha[j] = initAlpha
+	}
+	return &PReLULayer{Alpha: alpha, DAlpha: make([]float64, nFeatures)}, nil
+}
+
+// Forward applies the per-feature leaky slope and caches the inputs.
+func (pl *PReLULayer) Forward(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	if err := validateFeatures(inputs, len(pl.Alpha)); err != nil {
+		return nil, err
+	}
+
+	pl.Input = inputs
+	output := mathx.NewTensor(inputs.Shape()...)
+	outRows := output.Rows()
+	for i, sample := range inputs.Rows() {
+		for j, x := range sample {
+			if x > 0 {
+				outRows[i][j] = x
+			} else {
+				outRows[i][j] = pl.Alpha[j] * x
+			}
 		}
-		dl.DBiases[i] = grad / batchSize
 	}
+	return output, nil
+}
 
-	// Compute gradient for inputs
-	dInputs := make([][]float64, len(dl.Input))
-	for i := range dl.Input {
-		dInputs[i] = make([]float64, len(dl.Weights[0]))
-		for j := 0; j < len(dl.Weights[0]); j++ {
-			for n := 0; n < len(dl.Weights); n++ {
-				dInputs[i][j] += dOutputs[i][n] * dl.Weights[n][j]
+// Backward computes DAlpha (averaged over the batch, like DenseLayer) and
+// returns the gradient with respect to the inputs.
+func (pl *PReLULayer) Backward(dOutputs *mathx.Tensor) (*mathx.Tensor, error) {
+	if pl.Input == nil {
+		return nil, errors.New("backward called before forward")
+	}
+	if err := validateGradients(dOutputs, pl.Input); err != nil {
+		return nil, err
+	}
+	if len(pl.DAlpha) != len(pl.Alpha) {
+		pl.DAlpha = make([]float64, len(pl.Alpha))
+	}
+
+	inRows, dOutRows := pl.Input.Rows(), dOutputs.Rows()
+	batchSize := float64(len(inRows))
+	for j := range pl.DAlpha {
+		pl.DAlpha[j] = 0
+	}
+
+	dInputs := mathx.NewTensor(dOutputs.Shape()...)
+	dInRows := dInputs.Rows()
+	for i, sample := range inRows {
+		for j, x := range sample {
+			if x > 0 {
+				dInRows[i][j] = dOutRows[i][j]
+			} else {
+				dInRows[i][j] = pl.Alpha[j] * dOutRows[i][j]
+				pl.DAlpha[j] += x * dOutRows[i][j] / batchSize
 			}
 		}
 	}
+	return dInputs, nil
+}
 
-	// Update weights and biases
-	for i := range dl.Weights {
-		for j := range dl.Weights[i] {
-			dl.Weights[i][j] -= learningRate * dl.DWeights[i][j]
+// Params returns the slope vector. The returned slice aliases the layer's storage.
+func (pl *PReLULayer) Params() [][]float64 { return [][]float64{pl.Alpha} }
+
+// Grads returns the slope gradient, in the same order as Params.
+func (pl *PReLULayer) Grads() [][]float64 { return [][]float64{pl.DAlpha} }
+
+// SwishLayer is Swish with one learnable beta per feature:
+// f(x) = x * sigmoid(beta_j * x). beta = 1 gives SiLU.
+type SwishLayer struct {
+	Beta []float64
+
+	//! Cache for backpropagation
+	Input *mathx.Tensor
+	DBeta []float64
+}
+
+// NewSwishLayer creates a Swish layer for nFeatures inputs with every beta set to initBeta.
+func NewSwishLayer(nFeatures int, initBeta float64) (*SwishLayer, error) {
+	if nFeatures <= 0 {
+		return nil, errors.New("features must be positive")
+	}
+	beta := make([]float64, nFeatures)
+	for j := range beta {
+		beta[j] = initBeta
+	}
+	return &SwishLayer{Beta: beta, DBeta: make([]float64, nFeatures)}, nil
+}
+
+// Forward applies x * sigmoid(beta * x) and caches the inputs.
+func (sl *SwishLayer) Forward(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	if err := validateFeatures(inputs, len(sl.Beta)); err != nil {
+		return nil, err
+	}
+
+	sl.Input = inputs
+	output := mathx.NewTensor(inputs.Shape()...)
+	outRows := output.Rows()
+	for i, sample := range inputs.Rows() {
+		for j, x := range sample {
+			outRows[i][j] = x * stableSigmoid(sl.Beta[j]*x)
 		}
-		dl.Biases[i] -= learningRate * dl.DBiases[i]
 	}
+	return output, nil
+}
+
+// Backward computes DBeta (averaged over the batch, like DenseLayer) and
+// returns the gradient with respect to the inputs:
+//
+//	df/dx = s + βx·s(1 - s),  df/dβ = x²·s(1 - s),  with s = sigmoid(βx)
+func (sl *SwishLayer) Backward(dOutputs *mathx.Tensor) (*mathx.Tensor, error) {
+	if sl.Input == nil {
+		return nil, errors.New("backward called before forward")
+	}
+	if err := validateGradients(dOutputs, sl.Input); err != nil {
+		return nil, err
+	}
+	if len(sl.DBeta) != len(sl.Beta) {
+		sl.DBeta = make([]float64, len(sl.Beta))
+	}
+
+	inRows, dOutRows := sl.Input.Rows(), dOutputs.Rows()
+	batchSize := float64(len(inRows))
+	for j := range sl.DBeta {
+		sl.DBeta[j] = 0
+	}
+
+	dInputs := mathx.NewTensor(dOutputs.Shape()...)
+	dInRows := dInputs.Rows()
+	for i, sample := range inRows {
+		for j, x := range sample {
+			s := stableSigmoid(sl.Beta[j] * x)
+			ds := s * (1 - s)
+			dInRows[i][j] = dOutRows[i][j] * (s + sl.Beta[j]*x*ds)
+			sl.DBeta[j] += dOutRows[i][j] * x * x * ds / batchSize
+		}
+	}
+	return dInputs, nil
+}
+
+// Params returns the beta vector. The returned slice aliases the layer's storage.
+func (sl *SwishLayer) Params() [][]float64 { return [][]float64{sl.Beta} }
 
-	return dInputs
+// Grads returns the beta gradient, in the same order as Params.
+func (sl *SwishLayer) Grads() [][]float64 { return [][]float64{sl.DBeta} }
+
+//? ------------------------------
+//? Dropout
+//? ------------------------------
+
+// DropoutLayer zeroes each input with probability Rate while training and
+// scales the survivors by 1/(1-Rate) (inverted dropout), so it is the
+// identity at inference time and needs no rescaling there.
+type DropoutLayer struct {
+	Rate float64
+	// Training enables dropout; Sequential.SetTraining toggles it.
+	Training bool
+
+	//! Cache for backpropagation
+	Mask *mathx.Tensor
+
+	rng *rng.RNG
+}
+
+// NewDropoutLayer creates a dropout layer in training mode that draws its
+// masks from r. A nil r uses a time-seeded generator.
+func NewDropoutLayer(rate float64, r *rng.RNG) (*DropoutLayer, error) {
+	if !(rate >= 0 && rate < 1) {
+		return nil, fmt.Errorf("dropout rate must be in [0, 1), got %v", rate)
+	}
+	if r == nil {
+		r = rng.NewFromTime()
+	}
+	return &DropoutLayer{Rate: rate, Training: true, rng: r}, nil
+}
+
+// SetTraining switches between training (random masks) and inference (identity).
+func (dl *DropoutLayer) SetTraining(training bool) { dl.Training = training }
+
+// Forward applies a fresh random mask in training mode and caches it.
+func (dl *DropoutLayer) Forward(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	if inputs == nil || inputs.Size() == 0 {
+		return nil, errors.New("empty input")
+	}
+
+	keep := 1 - dl.Rate
+	dl.Mask = mathx.NewTensor(inputs.Shape()...)
+	output := inputs.Clone()
+	mask, out := dl.Mask.Data(), output.Data()
+	for i := range out {
+		switch {
+		case !dl.Training:
+			mask[i] = 1
+		case dl.rng.Float64() < keep:
+			mask[i] = 1 / keep
+		}
+		out[i] *= mask[i]
+	}
+	return output, nil
+}
+
+// Backward routes the gradient through the kept inputs only.
+func (dl *DropoutLayer) Backward(dOutputs *mathx.Tensor) (*mathx.Tensor, error) {
+	if dl.Mask == nil {
+		return nil, errors.New("backward called before forward")
+	}
+	if err := validateGradients(dOutputs, dl.Mask); err != nil {
+		return nil, err
+	}
+
+	dInputs := dOutputs.Clone()
+	d, mask := dInputs.Data(), dl.Mask.Data()
+	for i := range d {
+		d[i] *= mask[i]
+	}
+	return dInputs, nil
+}
+
+// Params returns nil; dropout layers are not trainable.
+func (dl *DropoutLayer) Params() [][]float64 { return nil }
+
+// Grads returns nil; dropout layers are not trainable.
+func (dl *DropoutLayer) Grads() [][]float64 { return nil }
+
+// randSource returns the generator behind the masks so checkpoints can save it.
+func (dl *DropoutLayer) randSource() *rng.RNG { return dl.rng }
+
+// setRandSource replaces the generator behind the masks.
+func (dl *DropoutLayer) setRandSource(r *rng.RNG) { dl.rng = r }
+
+// randomLayer is implemented by layers that draw random numbers while
+// training; checkpoints save and restore their generators.
+type randomLayer interface {
+	Layer
+	randSource() *rng.RNG
+	setRandSource(r *rng.RNG)
+}
+
+// validateFeatures checks that inputs is non-empty and its last axis has nFeatures values.
+func validateFeatures(inputs *mathx.Tensor, nFeatures int) error {
+	if inputs == nil || inputs.Size() == 0 {
+		return errors.New("empty input")
+	}
+	if got := inputs.Dim(inputs.Dims() - 1); got != nFeatures {
+		return fmt.Errorf("input has %d features, expected %d", got, nFeatures)
+	}
+	return nil
 }
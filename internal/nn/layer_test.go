@@ -0,0 +1,107 @@
+package nn
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// checkLayerGradients runs layer forward and backward with a fixed upstream
+// gradient, so the checked loss is L = Σ upstream ⊙ layer(x), and compares
+// dInputs and every Grads vector with central differences. Parameter
+// gradients are averaged over the batch, so they are compared with dL/dθ / N.
+// Parameters are perturbed through Params, which must alias the layer's
+// storage.
+func checkLayerGradients(t *testing.T, name string, layer Layer, inputs, upstream [][]float64) {
+	t.Helper()
+	const h = 1e-6
+
+	loss := func(x [][]float64) float64 {
+		out, err := layer.Forward(tensor(t, x))
+		if err != nil {
+			t.Fatalf("%s: Forward() returned error: %v", name, err)
+		}
+		var sum float64
+		for i, row := range out.Rows() {
+			for j := range row {
+				sum += upstream[i][j] * row[j]
+			}
+		}
+		return sum
+	}
+	check := func(what string, got, want float64) {
+		if !almostEqual(got, want, 1e-6*math.Max(1, math.Abs(want))) {
+			t.Errorf("%s: %s = %v; want %v", name, what, got, want)
+		}
+	}
+
+	x := copyMatrix(inputs)
+	loss(x)
+	dInputs, err := layer.Backward(tensor(t, upstream))
+	if err != nil {
+		t.Fatalf("%s: Backward() returned error: %v", name, err)
+	}
+	got := dInputs.Rows()
+	grads := CloneParams(layer.Grads())
+
+	for i := range x {
+		for j := range x[i] {
+			orig := x[i][j]
+			x[i][j] = orig + h
+			plus := loss(x)
+			x[i][j] = orig - h
+			minus := loss(x)
+			x[i][j] = orig
+			check(fmt.Sprintf("dInputs[%d][%d]", i, j), got[i][j], (plus-minus)/(2*h))
+		}
+	}
+
+	batchSize := float64(len(x))
+	for k, p := range layer.Params() {
+		for j := range p {
+			orig := p[j]
+			p[j] = orig + h
+			plus := loss(x)
+			p[j] = orig - h
+			minus := loss(x)
+			p[j] = orig
+			check(fmt.Sprintf("grad[%d][%d]", k, j), grads[k][j], (plus-minus)/(2*h)/batchSize)
+		}
+	}
+}
+
+func TestPReLULayerGradients(t *testing.T) {
+	layer, err := NewPReLULayer(4, 0.25)
+	if err != nil {
+		t.Fatalf("NewPReLULayer() returned error: %v", err)
+	}
+	copy(layer.Alpha, []float64{0.25, -0.1, 0.6, 0.05})
+	// Every feature sees both signs, so each slope receives a gradient.
+	inputs := [][]float64{{-1.5, 0.7, -0.2, 2.0}, {0.4, -2.2, 1.1, -0.9}, {-0.3, -0.6, -1.7, 0.5}}
+	upstream := [][]float64{{0.5, -1.0, 0.3, 0.8}, {1.2, 0.4, -0.7, 2.0}, {-0.6, 0.9, 1.5, -0.2}}
+	checkLayerGradients(t, "prelu", layer, inputs, upstream)
+
+	if _, err := layer.Forward(tensor(t, [][]float64{{1, 2}})); err == nil {
+		t.Error("PReLU Forward() expected error on feature mismatch, got nil")
+	}
+}
+
+func TestSwishLayerGradients(t *testing.T) {
+	layer, err := NewSwishLayer(4, 1)
+	if err != nil {
+		t.Fatalf("NewSwishLayer() returned error: %v", err)
+	}
+	copy(layer.Beta, []float64{1, 0.5, 2.5, -0.8})
+	inputs := [][]float64{{-1.5, 0.7, -0.2, 2.0}, {0.4, -2.2, 1.1, -0.9}, {-0.3, -0.6, -1.7, 0.5}}
+	upstream := [][]float64{{0.5, -1.0, 0.3, 0.8}, {1.2, 0.4, -0.7, 2.0}, {-0.6, 0.9, 1.5, -0.2}}
+	checkLayerGradients(t, "swish", layer, inputs, upstream)
+
+	// β = 1 is SiLU.
+	out, err := layer.Forward(tensor(t, [][]float64{{2, 0, 0, 0}}))
+	if err != nil {
+		t.Fatalf("Forward() returned error: %v", err)
+	}
+	if want := 2 / (1 + math.Exp(-2)); !almostEqual(out.At(0, 0), want, 1e-12) {
+		t.Errorf("Swish(β=1)(2) = %v; want %v", out.At(0, 0), want)
+	}
+}
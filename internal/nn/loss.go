@@ -3,58 +3,1383 @@ package nn
 import (
 	"fmt"
 	"math"
+
+	"github.com/SobhanYasami/nn-go/internal/mathx"
 )
 
 // LossFn represents a collection of loss functions.
 type LossFn struct{}
 
+// CrossEntropyOption configures the categorical cross-entropy losses.
+type CrossEntropyOption func(*crossEntropyConfig)
+
+// crossEntropyConfig holds the optional weighting and smoothing settings.
+type crossEntropyConfig struct {
+	classWeights   []float64
+	sampleWeights  []float64
+	labelSmoothing float64
+}
+
+// WithClassWeights scales each sample's loss by the weight of its true class
+// (for soft targets, by the target-weighted average of the class weights).
+func WithClassWeights(weights []float64) CrossEntropyOption {
+	return func(c *crossEntropyConfig) { c.classWeights = weights }
+}
+
+// WithSampleWeights scales the loss of sample i by weights[i].
+func WithSampleWeights(weights []float64) CrossEntropyOption {
+	return func(c *crossEntropyConfig) { c.sampleWeights = weights }
+}
+
+// WithLabelSmoothing mixes the targets with the uniform distribution:
+// t' = (1-ε)·t + ε/K.
+func WithLabelSmoothing(epsilon float64) CrossEntropyOption {
+	return func(c *crossEntropyConfig) { c.labelSmoothing = epsilon }
+}
+
 // CategoricalCrossEntropy computes the mean categorical cross-entropy loss.
 //
 // Arguments:
-//   - predictions: [][]float64 (softmax outputs, probabilities for each class)
+//   - predictions: *mathx.Tensor (N×C softmax outputs, probabilities for each class)
 //   - yTrue: []int (true class indices, e.g. [0, 2, 1, ...])
+//   - opts: optional class weights, sample weights and label smoothing
+//
+// Formula (t = smoothed one-hot target, w = class weight × sample weight):
+//
+//	L = - Σ_i w_i Σ_k t_ik log(p_ik) / Σ_i w_i
+//
+// Without options this is L = - (1/N) * Σ log(p[class_true]).
+func (lf *LossFn) CategoricalCrossEntropy(predictions *mathx.Tensor, yTrue []int, opts ...CrossEntropyOption) (float64, error) {
+	if err := validateLabels(predictions, yTrue); err != nil {
+		return 0, err
+	}
+
+	cfg, err := newCrossEntropyConfig(predictions.Dim(1), predictions.Dim(0), opts)
+	if err != nil {
+		return 0, err
+	}
+	return cfg.loss(predictions.Rows(), oneHot(yTrue, predictions.Dim(1)))
+}
+
+// SoftmaxCrossEntropyBackward computes the fused softmax + cross-entropy
+// gradient with respect to the logits, given softmax outputs:
+//
+//	dL/dz_i = w_i · (p_i - t_i) / Σ w
+//
+// It accepts the same options as CategoricalCrossEntropy and validates the
+// labels and options the same way. The result already includes the softmax
+// Jacobian, so it must not be passed through a Softmax layer's Backward; use
+// CategoricalCrossEntropyBackward for that.
+func (lf *LossFn) SoftmaxCrossEntropyBackward(predictions *mathx.Tensor, yTrue []int, opts ...CrossEntropyOption) (*mathx.Tensor, error) {
+	if err := validateLabels(predictions, yTrue); err != nil {
+		return nil, err
+	}
+
+	cfg, err := newCrossEntropyConfig(predictions.Dim(1), predictions.Dim(0), opts)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.backward(predictions.Rows(), oneHot(yTrue, predictions.Dim(1)))
+}
+
+// CategoricalCrossEntropySoft is CategoricalCrossEntropy for soft targets:
+// each row of targets is a probability distribution over the classes
+// (one-hot rows reproduce the integer-label version).
+func (lf *LossFn) CategoricalCrossEntropySoft(predictions, targets *mathx.Tensor, opts ...CrossEntropyOption) (float64, error) {
+	if err := validateDistributions(predictions, targets); err != nil {
+		return 0, err
+	}
+	cfg, err := newCrossEntropyConfig(predictions.Dim(1), predictions.Dim(0), opts)
+	if err != nil {
+		return 0, err
+	}
+	return cfg.loss(predictions.Rows(), targets.Rows())
+}
+
+// SoftmaxCrossEntropySoftBackward is the fused softmax + cross-entropy
+// gradient with respect to the logits for soft targets.
+func (lf *LossFn) SoftmaxCrossEntropySoftBackward(predictions, targets *mathx.Tensor, opts ...CrossEntropyOption) (*mathx.Tensor, error) {
+	if err := validateDistributions(predictions, targets); err != nil {
+		return nil, err
+	}
+	cfg, err := newCrossEntropyConfig(predictions.Dim(1), predictions.Dim(0), opts)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.backward(predictions.Rows(), targets.Rows())
+}
+
+// CategoricalCrossEntropyBackward computes the cross-entropy gradient with
+// respect to the predicted probabilities:
+//
+//	dL/dp_ik = -w_i · t_ik / (p_ik · Σ w)
+//
+// Use it when softmax is a separate layer: the Softmax backward applies the
+// Jacobian and yields the same logit gradient as SoftmaxCrossEntropyBackward.
+func (lf *LossFn) CategoricalCrossEntropyBackward(predictions *mathx.Tensor, yTrue []int, opts ...CrossEntropyOption) (*mathx.Tensor, error) {
+	if err := validateLabels(predictions, yTrue); err != nil {
+		return nil, err
+	}
+	cfg, err := newCrossEntropyConfig(predictions.Dim(1), predictions.Dim(0), opts)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.probBackward(predictions.Rows(), oneHot(yTrue, predictions.Dim(1)))
+}
+
+// CategoricalCrossEntropySoftBackward is CategoricalCrossEntropyBackward for soft targets.
+func (lf *LossFn) CategoricalCrossEntropySoftBackward(predictions, targets *mathx.Tensor, opts ...CrossEntropyOption) (*mathx.Tensor, error) {
+	if err := validateDistributions(predictions, targets); err != nil {
+		return nil, err
+	}
+	cfg, err := newCrossEntropyConfig(predictions.Dim(1), predictions.Dim(0), opts)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.probBackward(predictions.Rows(), targets.Rows())
+}
+
+// CategoricalCrossEntropyWithLogits computes cross-entropy directly from raw
+// logits using a log-softmax, so it never takes the log of an underflowed
+// probability. targets are distributions (use one-hot rows for hard labels).
+func (lf *LossFn) CategoricalCrossEntropyWithLogits(logits, targets *mathx.Tensor, opts ...CrossEntropyOption) (float64, error) {
+	if err := validateDistributions(logits, targets); err != nil {
+		return 0, err
+	}
+	cfg, err := newCrossEntropyConfig(logits.Dim(1), logits.Dim(0), opts)
+	if err != nil {
+		return 0, err
+	}
+	logProbs, err := NewActivationFn().LogSoftmax(logits)
+	if err != nil {
+		return 0, err
+	}
+	return cfg.logLoss(logProbs.Rows(), targets.Rows())
+}
+
+// CategoricalCrossEntropyWithLogitsBackward returns the gradient of
+// CategoricalCrossEntropyWithLogits with respect to the logits,
+// w_i · (softmax(z_i) - t_i) / Σ w.
+func (lf *LossFn) CategoricalCrossEntropyWithLogitsBackward(logits, targets *mathx.Tensor, opts ...CrossEntropyOption) (*mathx.Tensor, error) {
+	if err := validateDistributions(logits, targets); err != nil {
+		return nil, err
+	}
+	probs, err := NewActivationFn().Softmax(logits)
+	if err != nil {
+		return nil, err
+	}
+	return lf.SoftmaxCrossEntropySoftBackward(probs, targets, opts...)
+}
+
+// NLLLoss computes the mean negative log-likelihood of the true classes from
+// log-probabilities, typically the output of a LogSoftmax layer. It accepts
+// the same options as CategoricalCrossEntropy.
 //
 // Formula:
 //
-//	L = - (1/N) * Σ log(p[class_true])
-func (lf *LossFn) CategoricalCrossEntropy(predictions [][]float64, yTrue []int) (float64, error) {
-	if len(predictions) == 0 {
-		return 0, fmt.Errorf("predictions cannot be empty")
+//	L = -(1/N) * Σ log_p[class_true]
+func (lf *LossFn) NLLLoss(logProbs *mathx.Tensor, yTrue []int, opts ...CrossEntropyOption) (float64, error) {
+	if err := validateLabels(logProbs, yTrue); err != nil {
+		return 0, err
+	}
+	cfg, err := newCrossEntropyConfig(logProbs.Dim(1), logProbs.Dim(0), opts)
+	if err != nil {
+		return 0, err
 	}
-	if len(predictions) != len(yTrue) {
-		return 0, fmt.Errorf("predictions and labels must have the same length")
+	return cfg.logLoss(logProbs.Rows(), oneHot(yTrue, logProbs.Dim(1)))
+}
+
+// NLLLossBackward computes the gradient with respect to the log-probabilities:
+// -1/N at each true class (weighted and smoothed when options are given).
+func (lf *LossFn) NLLLossBackward(logProbs *mathx.Tensor, yTrue []int, opts ...CrossEntropyOption) (*mathx.Tensor, error) {
+	if err := validateLabels(logProbs, yTrue); err != nil {
+		return nil, err
+	}
+	cfg, err := newCrossEntropyConfig(logProbs.Dim(1), logProbs.Dim(0), opts)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.logBackward(oneHot(yTrue, logProbs.Dim(1)))
+}
+
+// newCrossEntropyConfig applies opts and validates them against the batch shape.
+func newCrossEntropyConfig(classes, samples int, opts []CrossEntropyOption) (*crossEntropyConfig, error) {
+	cfg := &crossEntropyConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if cfg.classWeights != nil && len(cfg.classWeights) != classes {
+		return nil, fmt.Errorf("got %d class weights for %d classes", len(cfg.classWeights), classes)
+	}
+	if cfg.sampleWeights != nil && len(cfg.sampleWeights) != samples {
+		return nil, fmt.Errorf("got %d sample weights for %d samples", len(cfg.sampleWeights), samples)
+	}
+	for _, weights := range [][]float64{cfg.classWeights, cfg.sampleWeights} {
+		for _, w := range weights {
+			if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+				return nil, fmt.Errorf("weights must be finite and non-negative, got %v", w)
+			}
+		}
 	}
+	if !(cfg.labelSmoothing >= 0 && cfg.labelSmoothing < 1) {
+		return nil, fmt.Errorf("label smoothing must be in [0, 1), got %v", cfg.labelSmoothing)
+	}
+	return cfg, nil
+}
 
+// smooth returns the label-smoothed version of a target row.
+func (c *crossEntropyConfig) smooth(target []float64) []float64 {
+	if c.labelSmoothing == 0 {
+		return target
+	}
+	uniform := c.labelSmoothing / float64(len(target))
+	smoothed := make([]float64, len(target))
+	for k, t := range target {
+		smoothed[k] = (1-c.labelSmoothing)*t + uniform
+	}
+	return smoothed
+}
+
+// weights returns the per-sample weights and their sum.
+func (c *crossEntropyConfig) weights(targets [][]float64) ([]float64, float64, error) {
+	weights := make([]float64, len(targets))
+	var total float64
+	for i, target := range targets {
+		w := 1.0
+		if c.classWeights != nil {
+			w = 0
+			for k, t := range target {
+				w += c.classWeights[k] * t
+			}
+		}
+		if c.sampleWeights != nil {
+			w *= c.sampleWeights[i]
+		}
+		weights[i] = w
+		total += w
+	}
+	if total <= 0 {
+		return nil, 0, fmt.Errorf("total sample weight must be positive")
+	}
+	return weights, total, nil
+}
+
+// loss computes the weighted mean cross-entropy against (unsmoothed) targets.
+func (c *crossEntropyConfig) loss(predictions, targets [][]float64) (float64, error) {
 	epsilon := 1e-15 // small value to prevent log(0)
+	logProbs := make([][]float64, len(predictions))
+	for i := range predictions {
+		logProbs[i] = make([]float64, len(predictions[i]))
+		for k, p := range predictions[i] {
+			logProbs[i][k] = math.Log(math.Max(p, epsilon))
+		}
+	}
+	return c.logLoss(logProbs, targets)
+}
+
+// logLoss computes the weighted mean cross-entropy from log-probabilities.
+func (c *crossEntropyConfig) logLoss(logProbs, targets [][]float64) (float64, error) {
+	weights, total, err := c.weights(targets)
+	if err != nil {
+		return 0, err
+	}
+
 	var sumLoss float64
+	for i := range logProbs {
+		if weights[i] == 0 {
+			continue
+		}
+		var sampleLoss float64
+		for k, t := range c.smooth(targets[i]) {
+			if t == 0 {
+				continue
+			}
+			sampleLoss -= t * logProbs[i][k]
+		}
+		sumLoss += weights[i] * sampleLoss
+	}
+	return sumLoss / total, nil
+}
+
+// logBackward computes the gradient w.r.t. the log-probabilities: -w_i·t_ik / Σw.
+func (c *crossEntropyConfig) logBackward(targets [][]float64) (*mathx.Tensor, error) {
+	weights, total, err := c.weights(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	dInputs, rows := newMatrix(len(targets), len(targets[0]))
+	for i := range targets {
+		scale := weights[i] / total
+		for k, t := range c.smooth(targets[i]) {
+			rows[i][k] = -scale * t
+		}
+	}
+	return dInputs, nil
+}
 
+// probBackward computes the gradient w.r.t. the probabilities: -w_i·t_ik / (p_ik·Σw).
+func (c *crossEntropyConfig) probBackward(predictions, targets [][]float64) (*mathx.Tensor, error) {
+	dInputs, err := c.logBackward(targets)
+	if err != nil {
+		return nil, err
+	}
+	for i, row := range dInputs.Rows() {
+		for k := range row {
+			row[k] /= clipProbability(predictions[i][k])
+		}
+	}
+	return dInputs, nil
+}
+
+// backward computes the fused softmax + cross-entropy gradient w.r.t. the logits.
+func (c *crossEntropyConfig) backward(predictions, targets [][]float64) (*mathx.Tensor, error) {
+	weights, total, err := c.weights(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	dInputs, rows := newMatrix(len(predictions), len(predictions[0]))
 	for i := range predictions {
-		classIdx := yTrue[i]
-		if classIdx < 0 || classIdx >= len(predictions[i]) {
-			return 0, fmt.Errorf("invalid class index %d at sample %d", classIdx, i)
+		scale := weights[i] / total
+		smoothed := c.smooth(targets[i])
+		for k := range predictions[i] {
+			rows[i][k] = scale * (predictions[i][k] - smoothed[k])
+		}
+	}
+	return dInputs, nil
+}
+
+// oneHot expands class indices into one-hot rows of the given width.
+func oneHot(labels []int, classes int) [][]float64 {
+	targets := make([][]float64, len(labels))
+	for i, classIdx := range labels {
+		targets[i] = make([]float64, classes)
+		targets[i][classIdx] = 1
+	}
+	return targets
+}
+
+//? ------------------------------
+//? Regression Losses
+//? ------------------------------
+
+// MeanSquaredError computes the mean squared error over all elements.
+//
+// Arguments:
+//   - predictions: *mathx.Tensor (model outputs)
+//   - yTrue: *mathx.Tensor (targets, same shape as predictions)
+//
+// Formula:
+//
+//	L = (1/(N·D)) * Σ (p - y)²
+func (lf *LossFn) MeanSquaredError(predictions, yTrue *mathx.Tensor) (float64, error) {
+	if err := validateTargets(predictions, yTrue); err != nil {
+		return 0, err
+	}
+
+	var sumLoss float64
+	p, y := predictions.Data(), yTrue.Data()
+	for i := range p {
+		diff := p[i] - y[i]
+		sumLoss += diff * diff
+	}
+	return sumLoss / float64(len(p)), nil
+}
+
+// MeanSquaredErrorBackward computes dL/dp = 2(p - y) / (N·D).
+func (lf *LossFn) MeanSquaredErrorBackward(predictions, yTrue *mathx.Tensor) (*mathx.Tensor, error) {
+	if err := validateTargets(predictions, yTrue); err != nil {
+		return nil, err
+	}
+
+	scale := 2 / float64(predictions.Size())
+	return elementwiseGrad(predictions, yTrue, func(p, y float64) float64 {
+		return scale * (p - y)
+	}), nil
+}
+
+// MeanAbsoluteError computes the mean absolute error over all elements.
+//
+// Formula:
+//
+//	L = (1/(N·D)) * Σ |p - y|
+func (lf *LossFn) MeanAbsoluteError(predictions, yTrue *mathx.Tensor) (float64, error) {
+	if err := validateTargets(predictions, yTrue); err != nil {
+		return 0, err
+	}
+
+	var sumLoss float64
+	p, y := predictions.Data(), yTrue.Data()
+	for i := range p {
+		sumLoss += math.Abs(p[i] - y[i])
+	}
+	return sumLoss / float64(len(p)), nil
+}
+
+// MeanAbsoluteErrorBackward computes dL/dp = sign(p - y) / (N·D),
+// using a zero subgradient where p == y.
+func (lf *LossFn) MeanAbsoluteErrorBackward(predictions, yTrue *mathx.Tensor) (*mathx.Tensor, error) {
+	if err := validateTargets(predictions, yTrue); err != nil {
+		return nil, err
+	}
+
+	scale := 1 / float64(predictions.Size())
+	return elementwiseGrad(predictions, yTrue, func(p, y float64) float64 {
+		switch {
+		case p > y:
+			return scale
+		case p < y:
+			return -scale
+		default:
+			return 0
+		}
+	}), nil
+}
+
+// Huber computes the mean Huber loss, quadratic for small errors and linear
+// beyond delta.
+//
+// Formula (e = p - y):
+//
+//	l(e) = 0.5 * e²                  if |e| <= δ
+//	l(e) = δ * (|e| - 0.5 * δ)       otherwise
+func (lf *LossFn) Huber(predictions, yTrue *mathx.Tensor, delta float64) (float64, error) {
+	if err := validateTargets(predictions, yTrue); err != nil {
+		return 0, err
+	}
+	if delta <= 0 {
+		return 0, fmt.Errorf("huber delta must be positive, got %v", delta)
+	}
+
+	var sumLoss float64
+	p, y := predictions.Data(), yTrue.Data()
+	for i := range p {
+		e := math.Abs(p[i] - y[i])
+		if e <= delta {
+			sumLoss += 0.5 * e * e
+		} else {
+			sumLoss += delta * (e - 0.5*delta)
+		}
+	}
+	return sumLoss / float64(len(p)), nil
+}
+
+// HuberBackward computes dL/dp = clip(p - y, -δ, δ) / (N·D).
+func (lf *LossFn) HuberBackward(predictions, yTrue *mathx.Tensor, delta float64) (*mathx.Tensor, error) {
+	if err := validateTargets(predictions, yTrue); err != nil {
+		return nil, err
+	}
+	if delta <= 0 {
+		return nil, fmt.Errorf("huber delta must be positive, got %v", delta)
+	}
+
+	scale := 1 / float64(predictions.Size())
+	return elementwiseGrad(predictions, yTrue, func(p, y float64) float64 {
+		return scale * math.Max(-delta, math.Min(delta, p-y))
+	}), nil
+}
+
+// LogCosh computes the mean log-cosh loss, which behaves like MSE/2 for small
+// errors and like MAE for large ones.
+//
+// Formula (e = p - y), evaluated stably as |e| + log1p(exp(-2|e|)) - log 2:
+//
+//	L = (1/(N·D)) * Σ log(cosh(e))
+func (lf *LossFn) LogCosh(predictions, yTrue *mathx.Tensor) (float64, error) {
+	if err := validateTargets(predictions, yTrue); err != nil {
+		return 0, err
+	}
+
+	var sumLoss float64
+	p, y := predictions.Data(), yTrue.Data()
+	for i := range p {
+		e := math.Abs(p[i] - y[i])
+		sumLoss += e + math.Log1p(math.Exp(-2*e)) - math.Ln2
+	}
+	return sumLoss / float64(len(p)), nil
+}
+
+// LogCoshBackward computes dL/dp = tanh(p - y) / (N·D).
+func (lf *LossFn) LogCoshBackward(predictions, yTrue *mathx.Tensor) (*mathx.Tensor, error) {
+	if err := validateTargets(predictions, yTrue); err != nil {
+		return nil, err
+	}
+
+	scale := 1 / float64(predictions.Size())
+	return elementwiseGrad(predictions, yTrue, func(p, y float64) float64 {
+		return scale * math.Tanh(p-y)
+	}), nil
+}
+
+//? ------------------------------
+//? Binary & Multi-Label Losses
+//? ------------------------------
+
+// BinaryCrossEntropy computes the mean binary cross-entropy of probabilities
+// (e.g. Sigmoid outputs). Each column is an independent binary problem, so a
+// matrix of 0/1 targets gives multi-label classification.
+//
+// Arguments:
+//   - predictions: *mathx.Tensor (probabilities in [0, 1])
+//   - yTrue: *mathx.Tensor (targets in [0, 1], same shape as predictions)
+//
+// Formula:
+//
+//	L = -(1/(N·D)) * Σ [y·log(p) + (1-y)·log(1-p)]
+func (lf *LossFn) BinaryCrossEntropy(predictions, yTrue *mathx.Tensor) (float64, error) {
+	if err := validateBinaryTargets(predictions, yTrue); err != nil {
+		return 0, err
+	}
+
+	var sumLoss float64
+	probs, targets := predictions.Data(), yTrue.Data()
+	for i := range probs {
+		p := clipProbability(probs[i])
+		y := targets[i]
+		sumLoss += -(y*math.Log(p) + (1-y)*math.Log(1-p))
+	}
+	return sumLoss / float64(len(probs)), nil
+}
+
+// BinaryCrossEntropyBackward computes the gradient with respect to the
+// probabilities, for use when a Sigmoid layer's own backward follows:
+//
+//	dL/dp = (p - y) / (p·(1-p)·N·D)
+func (lf *LossFn) BinaryCrossEntropyBackward(predictions, yTrue *mathx.Tensor) (*mathx.Tensor, error) {
+	if err := validateBinaryTargets(predictions, yTrue); err != nil {
+		return nil, err
+	}
+
+	scale := 1 / float64(predictions.Size())
+	return elementwiseGrad(predictions, yTrue, func(p, y float64) float64 {
+		p = clipProbability(p)
+		return scale * (p - y) / (p * (1 - p))
+	}), nil
+}
+
+// BinaryCrossEntropyWithLogits fuses Sigmoid and BinaryCrossEntropy on raw
+// logits, which avoids log(0) for saturated outputs.
+//
+// Formula (z = logit), evaluated as max(z, 0) - z·y + log(1 + exp(-|z|)):
+//
+//	L = -(1/(N·D)) * Σ [y·log σ(z) + (1-y)·log(1-σ(z))]
+func (lf *LossFn) BinaryCrossEntropyWithLogits(logits, yTrue *mathx.Tensor) (float64, error) {
+	if err := validateBinaryTargets(logits, yTrue); err != nil {
+		return 0, err
+	}
+
+	var sumLoss float64
+	z, y := logits.Data(), yTrue.Data()
+	for i := range z {
+		sumLoss += math.Max(z[i], 0) - z[i]*y[i] + math.Log1p(math.Exp(-math.Abs(z[i])))
+	}
+	return sumLoss / float64(len(z)), nil
+}
+
+// BinaryCrossEntropyWithLogitsBackward computes the gradient with respect to
+// the logits. As with SoftmaxCrossEntropyBackward, the activation derivative is
+// already included, so the result goes directly into the preceding DenseLayer:
+//
+//	dL/dz = (σ(z) - y) / (N·D)
+func (lf *LossFn) BinaryCrossEntropyWithLogitsBackward(logits, yTrue *mathx.Tensor) (*mathx.Tensor, error) {
+	if err := validateBinaryTargets(logits, yTrue); err != nil {
+		return nil, err
+	}
+
+	scale := 1 / float64(logits.Size())
+	return elementwiseGrad(logits, yTrue, func(z, y float64) float64 {
+		return scale * (stableSigmoid(z) - y)
+	}), nil
+}
+
+// SigmoidBinaryCrossEntropyBackward is the fused gradient with respect to the
+// logits given Sigmoid outputs, mirroring SoftmaxCrossEntropyBackward. Feed it
+// to the DenseLayer below the Sigmoid, not through SigmoidBackward:
+//
+//	dL/dz = (p - y) / (N·D)
+func (lf *LossFn) SigmoidBinaryCrossEntropyBackward(predictions, yTrue *mathx.Tensor) (*mathx.Tensor, error) {
+	if err := validateBinaryTargets(predictions, yTrue); err != nil {
+		return nil, err
+	}
+
+	scale := 1 / float64(predictions.Size())
+	return elementwiseGrad(predictions, yTrue, func(p, y float64) float64 {
+		return scale * (p - y)
+	}), nil
+}
+
+// MultiHot builds a multi-label target matrix: row i has 1 in every column
+// listed in labels[i] and 0 elsewhere.
+func MultiHot(labels [][]int, numClasses int) (*mathx.Tensor, error) {
+	if numClasses <= 0 {
+		return nil, fmt.Errorf("number of classes must be positive, got %d", numClasses)
+	}
+
+	targets, rows := newMatrix(len(labels), numClasses)
+	for i, row := range labels {
+		for _, classIdx := range row {
+			if classIdx < 0 || classIdx >= numClasses {
+				return nil, fmt.Errorf("invalid class index %d at sample %d", classIdx, i)
+			}
+			rows[i][classIdx] = 1
+		}
+	}
+	return targets, nil
+}
+
+//? ------------------------------
+//? Focal, Hinge & KL-Divergence Losses
+//? ------------------------------
+
+// FocalLoss computes the mean multi-class focal loss, which down-weights
+// well-classified samples so training focuses on hard, rare ones.
+//
+// Arguments:
+//   - predictions: *mathx.Tensor (N×C softmax outputs)
+//   - yTrue: []int (true class indices)
+//   - gamma: focusing parameter (0 reduces to cross-entropy)
+//   - alpha: per-class balancing weights, e.g. larger for rare classes
+//     (nil weights every class by 1)
+//
+// Formula (p_t = predicted probability of the true class t):
+//
+//	L = -(1/N) * Σ α_t·(1 - p_t)^γ·log(p_t)
+func (lf *LossFn) FocalLoss(predictions *mathx.Tensor, yTrue []int, gamma float64, alpha []float64) (float64, error) {
+	if err := validateLabels(predictions, yTrue); err != nil {
+		return 0, err
+	}
+	if err := validateFocalParams(gamma, alpha, predictions.Dim(1)); err != nil {
+		return 0, err
+	}
+
+	var sumLoss float64
+	for i, row := range predictions.Rows() {
+		t := yTrue[i]
+		pt := clipProbability(row[t])
+		sumLoss += -focalAlpha(alpha, t) * math.Pow(1-pt, gamma) * math.Log(pt)
+	}
+	return sumLoss / float64(predictions.Dim(0)), nil
+}
+
+// FocalLossBackward computes the focal-loss gradient with respect to the
+// predicted probabilities; only the true class of each sample is non-zero:
+//
+//	dL/dp_t = α_t·[γ(1-p_t)^(γ-1)·log(p_t) - (1-p_t)^γ / p_t] / N
+func (lf *LossFn) FocalLossBackward(predictions *mathx.Tensor, yTrue []int, gamma float64, alpha []float64) (*mathx.Tensor, error) {
+	if err := validateLabels(predictions, yTrue); err != nil {
+		return nil, err
+	}
+	if err := validateFocalParams(gamma, alpha, predictions.Dim(1)); err != nil {
+		return nil, err
+	}
+
+	samples := float64(predictions.Dim(0))
+	dInputs, dRows := newMatrix(predictions.Dim(0), predictions.Dim(1))
+	for i, row := range predictions.Rows() {
+		t := yTrue[i]
+		dRows[i][t] = focalGrad(row[t], gamma, focalAlpha(alpha, t)) / samples
+	}
+	return dInputs, nil
+}
+
+// SoftmaxFocalBackward computes the focal-loss gradient with respect to the
+// logits given softmax outputs, fusing the softmax Jacobian like
+// SoftmaxCrossEntropyBackward:
+//
+//	dL/dz_j = dL/dp_t · p_t · (δ_tj - p_j) / N
+//	dL/dp_t = α_t·[γ(1-p_t)^(γ-1)·log(p_t) - (1-p_t)^γ / p_t]
+func (lf *LossFn) SoftmaxFocalBackward(predictions *mathx.Tensor, yTrue []int, gamma float64, alpha []float64) (*mathx.Tensor, error) {
+	if err := validateLabels(predictions, yTrue); err != nil {
+		return nil, err
+	}
+	if err := validateFocalParams(gamma, alpha, predictions.Dim(1)); err != nil {
+		return nil, err
+	}
+
+	samples := float64(predictions.Dim(0))
+	dInputs, dRows := newMatrix(predictions.Dim(0), predictions.Dim(1))
+	for i, row := range predictions.Rows() {
+		t := yTrue[i]
+		pt := clipProbability(row[t])
+		dLdpt := focalGrad(pt, gamma, focalAlpha(alpha, t))
+
+		for j, p := range row {
+			delta := 0.0
+			if j == t {
+				delta = 1
+			}
+			dRows[i][j] = dLdpt * pt * (delta - p) / samples
+		}
+	}
+	return dInputs, nil
+}
+
+// MultiClassHinge computes the mean Weston–Watkins multi-class hinge loss on
+// raw scores (no softmax). With squared set, each margin violation is squared.
+//
+// Formula (s = scores, t = true class):
+//
+//	L = (1/N) * Σ_i Σ_{j≠t} max(0, 1 + s_j - s_t)        (hinge)
+//	L = (1/N) * Σ_i Σ_{j≠t} max(0, 1 + s_j - s_t)²       (squared hinge)
+func (lf *LossFn) MultiClassHinge(scores *mathx.Tensor, yTrue []int, squared bool) (float64, error) {
+	if err := validateLabels(scores, yTrue); err != nil {
+		return 0, err
+	}
+
+	var sumLoss float64
+	for i, row := range scores.Rows() {
+		t := yTrue[i]
+		for j, s := range row {
+			if j == t {
+				continue
+			}
+			margin := math.Max(0, 1+s-row[t])
+			if squared {
+				margin *= margin
+			}
+			sumLoss += margin
+		}
+	}
+	return sumLoss / float64(scores.Dim(0)), nil
+}
+
+// MultiClassHingeBackward computes the gradient with respect to the scores.
+// Every violating class j gets +g and the true class gets -g, where g is
+// 1/N for hinge and 2·margin/N for squared hinge.
+func (lf *LossFn) MultiClassHingeBackward(scores *mathx.Tensor, yTrue []int, squared bool) (*mathx.Tensor, error) {
+	if err := validateLabels(scores, yTrue); err != nil {
+		return nil, err
+	}
+
+	samples := float64(scores.Dim(0))
+	dInputs, dRows := newMatrix(scores.Dim(0), scores.Dim(1))
+	for i, row := range scores.Rows() {
+		t := yTrue[i]
+		for j, s := range row {
+			if j == t {
+				continue
+			}
+			margin := 1 + s - row[t]
+			if margin <= 0 {
+				continue
+			}
+			g := 1 / samples
+			if squared {
+				g = 2 * margin / samples
+			}
+			dRows[i][j] += g
+			dRows[i][t] -= g
+		}
+	}
+	return dInputs, nil
+}
+
+// KLDivergence computes the mean Kullback–Leibler divergence KL(target ‖ prediction)
+// between target distributions and predicted probabilities, e.g. for distillation.
+//
+// Formula:
+//
+//	L = (1/N) * Σ_i Σ_k t_ik · log(t_ik / p_ik)
+func (lf *LossFn) KLDivergence(predictions, targets *mathx.Tensor) (float64, error) {
+	if err := validateDistributions(predictions, targets); err != nil {
+		return 0, err
+	}
+
+	var sumLoss float64
+	p, t := predictions.Data(), targets.Data()
+	for i := range p {
+		if t[i] == 0 {
+			continue
+		}
+		sumLoss += t[i] * (math.Log(t[i]) - math.Log(clipProbability(p[i])))
+	}
+	return sumLoss / float64(predictions.Dim(0)), nil
+}
+
+// KLDivergenceBackward computes the gradient with respect to the predicted
+// probabilities, dL/dp = -t / (p·N), for use through a Softmax layer's backward.
+func (lf *LossFn) KLDivergenceBackward(predictions, targets *mathx.Tensor) (*mathx.Tensor, error) {
+	if err := validateDistributions(predictions, targets); err != nil {
+		return nil, err
+	}
+
+	samples := float64(predictions.Dim(0))
+	return elementwiseGrad(predictions, targets, func(p, t float64) float64 {
+		return -t / (clipProbability(p) * samples)
+	}), nil
+}
+
+// SoftmaxKLDivergenceBackward computes the fused gradient with respect to the
+// logits given softmax outputs. The target entropy is constant, so this equals
+// the soft-target cross-entropy gradient (p - t) / N.
+func (lf *LossFn) SoftmaxKLDivergenceBackward(predictions, targets *mathx.Tensor) (*mathx.Tensor, error) {
+	return lf.SoftmaxCrossEntropySoftBackward(predictions, targets)
+}
+
+//? ------------------------------
+//? Metric-Learning Losses
+//? ------------------------------
+
+// ContrastiveLoss computes the mean pairwise contrastive loss between two
+// batches of embeddings. Similar pairs are pulled together; dissimilar pairs
+// are pushed apart until their Euclidean distance reaches margin.
+//
+// Formula (d = ‖x1 - x2‖, y = 1 for similar pairs):
+//
+//	L = (1/N) * Σ [ y·d² + (1 - y)·max(0, m - d)² ]
+func (lf *LossFn) ContrastiveLoss(x1, x2 *mathx.Tensor, similar []bool, margin float64) (float64, error) {
+	if err := validatePairs(x1, x2, similar, margin); err != nil {
+		return 0, err
+	}
+
+	var sumLoss float64
+	rows1, rows2 := x1.Rows(), x2.Rows()
+	for i := range rows1 {
+		d := euclidean(rows1[i], rows2[i])
+		if similar[i] {
+			sumLoss += d * d
+		} else if d < margin {
+			sumLoss += (margin - d) * (margin - d)
+		}
+	}
+	return sumLoss / float64(len(rows1)), nil
+}
+
+// ContrastiveLossBackward returns the gradients with respect to both
+// embedding batches. dX2 is always the negation of dX1.
+func (lf *LossFn) ContrastiveLossBackward(x1, x2 *mathx.Tensor, similar []bool, margin float64) (*mathx.Tensor, *mathx.Tensor, error) {
+	if err := validatePairs(x1, x2, similar, margin); err != nil {
+		return nil, nil, err
+	}
+
+	rows1, rows2 := x1.Rows(), x2.Rows()
+	samples := float64(len(rows1))
+	dX1, dRows1 := newMatrix(x1.Dim(0), x1.Dim(1))
+	dX2, dRows2 := newMatrix(x2.Dim(0), x2.Dim(1))
+	for i := range rows1 {
+		// Coefficient of (x1 - x2) in the gradient.
+		var scale float64
+		d := euclidean(rows1[i], rows2[i])
+		switch {
+		case similar[i]:
+			scale = 2 / samples
+		case d < margin && d > 0:
+			scale = -2 * (margin - d) / (d * samples)
+		}
+		for k := range rows1[i] {
+			g := scale * (rows1[i][k] - rows2[i][k])
+			dRows1[i][k] = g
+			dRows2[i][k] = -g
+		}
+	}
+	return dX1, dX2, nil
+}
+
+// TripletBatchHard computes the batch-hard triplet loss. For every anchor the
+// farthest embedding with the same label (hardest positive) and the closest
+// embedding with a different label (hardest negative) are mined from the batch.
+// Anchors without a positive or a negative are skipped.
+//
+// Formula (averaged over valid anchors a):
+//
+//	L = (1/A) * Σ max(0, d(a, p_hard) - d(a, n_hard) + m)
+func (lf *LossFn) TripletBatchHard(embeddings *mathx.Tensor, labels []int, margin float64) (float64, error) {
+	triplets, err := mineBatchHard(embeddings, labels, margin)
+	if err != nil || len(triplets) == 0 {
+		return 0, err
+	}
+
+	var sumLoss float64
+	for _, tr := range triplets {
+		sumLoss += tr.loss
+	}
+	return sumLoss / float64(len(triplets)), nil
+}
+
+// TripletBatchHardBackward returns the gradient with respect to the
+// embeddings. Only the mined anchor, positive and negative of each active
+// triplet receive gradient.
+func (lf *LossFn) TripletBatchHardBackward(embeddings *mathx.Tensor, labels []int, margin float64) (*mathx.Tensor, error) {
+	triplets, err := mineBatchHard(embeddings, labels, margin)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := embeddings.Rows()
+	dInputs, dRows := newMatrix(embeddings.Dim(0), embeddings.Dim(1))
+	count := float64(len(triplets))
+	for _, tr := range triplets {
+		if tr.loss <= 0 {
+			continue
+		}
+		a, p, n := rows[tr.anchor], rows[tr.positive], rows[tr.negative]
+		for k := range a {
+			var gp, gn float64
+			if tr.dPos > 0 {
+				gp = (a[k] - p[k]) / tr.dPos / count
+			}
+			if tr.dNeg > 0 {
+				gn = (a[k] - n[k]) / tr.dNeg / count
+			}
+			dRows[tr.anchor][k] += gp - gn
+			dRows[tr.positive][k] -= gp
+			dRows[tr.negative][k] += gn
+		}
+	}
+	return dInputs, nil
+}
+
+// triplet is one mined anchor with its hardest positive and negative.
+type triplet struct {
+	anchor, positive, negative int
+	dPos, dNeg, loss           float64
+}
+
+// mineBatchHard selects the hardest positive and negative for every anchor.
+func mineBatchHard(embeddings *mathx.Tensor, labels []int, margin float64) ([]triplet, error) {
+	if err := validateEmbeddings(embeddings); err != nil {
+		return nil, err
+	}
+	rows := embeddings.Rows()
+	if len(labels) != len(rows) {
+		return nil, fmt.Errorf("embeddings and labels must have the same length")
+	}
+	if margin < 0 {
+		return nil, fmt.Errorf("margin must be non-negative, got %v", margin)
+	}
+
+	var triplets []triplet
+	for a := range rows {
+		tr := triplet{anchor: a, positive: -1, negative: -1, dNeg: math.Inf(1)}
+		for j := range rows {
+			if j == a {
+				continue
+			}
+			d := euclidean(rows[a], rows[j])
+			if labels[j] == labels[a] {
+				if tr.positive < 0 || d > tr.dPos {
+					tr.positive, tr.dPos = j, d
+				}
+			} else if d < tr.dNeg {
+				tr.negative, tr.dNeg = j, d
+			}
+		}
+		if tr.positive < 0 || tr.negative < 0 {
+			continue
+		}
+		tr.loss = math.Max(0, tr.dPos-tr.dNeg+margin)
+		triplets = append(triplets, tr)
+	}
+	return triplets, nil
+}
+
+// NTXent computes the normalized temperature-scaled cross-entropy loss used by
+// SimCLR. z holds 2N embeddings where rows i and i+N are two views of the same
+// sample; every other row in the batch acts as a negative. Similarities are
+// cosine similarities divided by temperature.
+//
+// Formula (s = cosine similarity, τ = temperature, p(i) = positive of i):
+//
+//	L = (1/2N) * Σ_i -log( exp(s_i,p(i)/τ) / Σ_{k≠i} exp(s_ik/τ) )
+func (lf *LossFn) NTXent(z *mathx.Tensor, temperature float64) (float64, error) {
+	loss, _, err := ntXent(z, temperature, false)
+	return loss, err
+}
+
+// NTXentBackward returns the gradient of NTXent with respect to z.
+func (lf *LossFn) NTXentBackward(z *mathx.Tensor, temperature float64) (*mathx.Tensor, error) {
+	_, grad, err := ntXent(z, temperature, true)
+	return grad, err
+}
+
+// ntXent evaluates NT-Xent and, when withGrad is set, its gradient.
+func ntXent(z *mathx.Tensor, temperature float64, withGrad bool) (float64, *mathx.Tensor, error) {
+	if err := validateEmbeddings(z); err != nil {
+		return 0, nil, err
+	}
+	if z.Dim(0)%2 != 0 {
+		return 0, nil, fmt.Errorf("NT-Xent needs an even number of embeddings (2N views), got %d", z.Dim(0))
+	}
+	if temperature <= 0 {
+		return 0, nil, fmt.Errorf("temperature must be positive, got %v", temperature)
+	}
+	u, norms, err := normalizeRows(z.Rows())
+	if err != nil {
+		return 0, nil, err
+	}
+
+	rows, half := len(u), len(u)/2
+	var dU [][]float64
+	if withGrad {
+		dU = zerosLike(u)
+	}
+
+	var sumLoss float64
+	for i := range u {
+		pos := (i + half) % rows
+		logits := make([]float64, rows)
+		maxLogit := math.Inf(-1)
+		for k := range u {
+			if k != i {
+				logits[k] = dot(u[i], u[k]) / temperature
+				maxLogit = math.Max(maxLogit, logits[k])
+			}
+		}
+		var sumExp float64
+		for k := range u {
+			if k != i {
+				sumExp += math.Exp(logits[k] - maxLogit)
+			}
+		}
+		logSumExp := maxLogit + math.Log(sumExp)
+		sumLoss += logSumExp - logits[pos]
+
+		if !withGrad {
+			continue
+		}
+		for k := range u {
+			if k == i {
+				continue
+			}
+			g := math.Exp(logits[k] - logSumExp)
+			if k == pos {
+				g--
+			}
+			g /= temperature * float64(rows)
+			for d := range u[i] {
+				dU[i][d] += g * u[k][d]
+				dU[k][d] += g * u[i][d]
+			}
+		}
+	}
+
+	loss := sumLoss / float64(rows)
+	if !withGrad {
+		return loss, nil, nil
+	}
+	return loss, normalizeBackward(dU, u, norms), nil
+}
+
+// InfoNCE computes the InfoNCE loss between query and key embeddings. Row i of
+// keys is the positive for row i of queries and all other keys are negatives.
+// Similarities are cosine similarities divided by temperature.
+//
+// Formula:
+//
+//	L = (1/N) * Σ_i -log( exp(s_ii/τ) / Σ_j exp(s_ij/τ) )
+func (lf *LossFn) InfoNCE(queries, keys *mathx.Tensor, temperature float64) (float64, error) {
+	loss, _, _, err := infoNCE(queries, keys, temperature, false)
+	return loss, err
+}
+
+// InfoNCEBackward returns the gradients with respect to queries and keys.
+func (lf *LossFn) InfoNCEBackward(queries, keys *mathx.Tensor, temperature float64) (*mathx.Tensor, *mathx.Tensor, error) {
+	_, dQ, dK, err := infoNCE(queries, keys, temperature, true)
+	return dQ, dK, err
+}
+
+// infoNCE evaluates InfoNCE and, when withGrad is set, its gradients.
+func infoNCE(queries, keys *mathx.Tensor, temperature float64, withGrad bool) (float64, *mathx.Tensor, *mathx.Tensor, error) {
+	if err := validateEmbeddings(queries); err != nil {
+		return 0, nil, nil, err
+	}
+	if err := validateEmbeddings(keys); err != nil {
+		return 0, nil, nil, err
+	}
+	if !mathx.SameShape(queries, keys) {
+		return 0, nil, nil, fmt.Errorf("queries and keys must have the same shape")
+	}
+	if temperature <= 0 {
+		return 0, nil, nil, fmt.Errorf("temperature must be positive, got %v", temperature)
+	}
+	q, qNorms, err := normalizeRows(queries.Rows())
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	k, kNorms, err := normalizeRows(keys.Rows())
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	rows := len(q)
+	var dQ, dK [][]float64
+	if withGrad {
+		dQ, dK = zerosLike(q), zerosLike(k)
+	}
+
+	var sumLoss float64
+	for i := range q {
+		logits := make([]float64, rows)
+		for j := range k {
+			logits[j] = dot(q[i], k[j]) / temperature
+		}
+		maxLogit := findMax(logits)
+		var sumExp float64
+		for _, l := range logits {
+			sumExp += math.Exp(l - maxLogit)
+		}
+		logSumExp := maxLogit + math.Log(sumExp)
+		sumLoss += logSumExp - logits[i]
+
+		if !withGrad {
+			continue
+		}
+		for j := range k {
+			g := math.Exp(logits[j] - logSumExp)
+			if j == i {
+				g--
+			}
+			g /= temperature * float64(rows)
+			for d := range q[i] {
+				dQ[i][d] += g * k[j][d]
+				dK[j][d] += g * q[i][d]
+			}
+		}
+	}
+
+	loss := sumLoss / float64(rows)
+	if !withGrad {
+		return loss, nil, nil, nil
+	}
+	return loss, normalizeBackward(dQ, q, qNorms), normalizeBackward(dK, k, kNorms), nil
+}
+
+//? ------------------------------
+//? Utility Functions
+//? ------------------------------
+
+// validateBatch checks that x is a non-empty N×D tensor.
+func validateBatch(x *mathx.Tensor, name string) error {
+	if x == nil || x.Size() == 0 {
+		return fmt.Errorf("%s cannot be empty", name)
+	}
+	if x.Dims() != 2 {
+		return fmt.Errorf("%s must be a 2-D tensor, got shape %v", name, x.Shape())
+	}
+	return nil
+}
+
+// validateTargets checks that predictions and dense targets are non-empty
+// matrices of identical shape.
+func validateTargets(predictions, yTrue *mathx.Tensor) error {
+	if err := validateBatch(predictions, "predictions"); err != nil {
+		return err
+	}
+	if yTrue == nil || !mathx.SameShape(predictions, yTrue) {
+		var shape []int
+		if yTrue != nil {
+			shape = yTrue.Shape()
+		}
+		return fmt.Errorf("targets have shape %v, expected %v", shape, predictions.Shape())
+	}
+	return nil
+}
+
+// validateLabels checks that predictions form a non-empty matrix with one row
+// per label and every label is a valid column index.
+func validateLabels(predictions *mathx.Tensor, yTrue []int) error {
+	if err := validateBatch(predictions, "predictions"); err != nil {
+		return err
+	}
+	if predictions.Dim(0) != len(yTrue) {
+		return fmt.Errorf("predictions and labels must have the same length")
+	}
+	cols := predictions.Dim(1)
+	for i, classIdx := range yTrue {
+		if classIdx < 0 || classIdx >= cols {
+			return fmt.Errorf("invalid class index %d at sample %d", classIdx, i)
+		}
+	}
+	return nil
+}
+
+// focalGrad returns the derivative of the focal term -α(1-p)^γ·log(p) with respect to p.
+func focalGrad(p, gamma, alpha float64) float64 {
+	p = clipProbability(p)
+	grad := -alpha * math.Pow(1-p, gamma) / p
+	if gamma != 0 {
+		grad += alpha * gamma * math.Pow(1-p, gamma-1) * math.Log(p)
+	}
+	return grad
+}
+
+// focalAlpha returns the balancing weight of class t, 1 when alpha is nil.
+func focalAlpha(alpha []float64, t int) float64 {
+	if alpha == nil {
+		return 1
+	}
+	return alpha[t]
+}
+
+// validateFocalParams checks the focal-loss hyper-parameters against the
+// number of classes.
+func validateFocalParams(gamma float64, alpha []float64, classes int) error {
+	if !(gamma >= 0) {
+		return fmt.Errorf("focal gamma must be non-negative, got %v", gamma)
+	}
+	if alpha == nil {
+		return nil
+	}
+	if len(alpha) != classes {
+		return fmt.Errorf("got %d focal alpha weights for %d classes", len(alpha), classes)
+	}
+	for k, a := range alpha {
+		if a < 0 || math.IsNaN(a) || math.IsInf(a, 0) {
+			return fmt.Errorf("focal alpha must be finite and non-negative, got %v for class %d", a, k)
 		}
+	}
+	return nil
+}
 
-		p := predictions[i][classIdx]
-		if p < epsilon {
-			p = epsilon
+// validateEmbeddings checks that an embedding batch is a non-empty N×D tensor.
+func validateEmbeddings(x *mathx.Tensor) error {
+	return validateBatch(x, "embeddings")
+}
+
+// validatePairs checks the inputs of the contrastive loss.
+func validatePairs(x1, x2 *mathx.Tensor, similar []bool, margin float64) error {
+	if err := validateEmbeddings(x1); err != nil {
+		return err
+	}
+	if err := validateEmbeddings(x2); err != nil {
+		return err
+	}
+	if !mathx.SameShape(x1, x2) {
+		return fmt.Errorf("embedding pairs must have the same shape")
+	}
+	if len(similar) != x1.Dim(0) {
+		return fmt.Errorf("embeddings and pair labels must have the same length")
+	}
+	if margin < 0 {
+		return fmt.Errorf("margin must be non-negative, got %v", margin)
+	}
+	return nil
+}
+
+// euclidean returns the Euclidean distance between two vectors.
+func euclidean(a, b []float64) float64 {
+	var sum float64
+	for k := range a {
+		d := a[k] - b[k]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// dot returns the inner product of two vectors.
+func dot(a, b []float64) float64 {
+	var sum float64
+	for k := range a {
+		sum += a[k] * b[k]
+	}
+	return sum
+}
+
+// normalizeRows scales every row to unit L2 norm and returns the norms.
+func normalizeRows(x [][]float64) ([][]float64, []float64, error) {
+	u := make([][]float64, len(x))
+	norms := make([]float64, len(x))
+	for i, row := range x {
+		norms[i] = math.Sqrt(dot(row, row))
+		if norms[i] == 0 {
+			return nil, nil, fmt.Errorf("embedding %d has zero norm", i)
+		}
+		u[i] = make([]float64, len(row))
+		for k, v := range row {
+			u[i][k] = v / norms[i]
+		}
+	}
+	return u, norms, nil
+}
+
+// normalizeBackward maps gradients w.r.t. unit vectors u = x/‖x‖ back to x:
+// dx = (du - u·(u·du)) / ‖x‖.
+func normalizeBackward(dU, u [][]float64, norms []float64) *mathx.Tensor {
+	dX, rows := newMatrix(len(u), len(u[0]))
+	for i := range u {
+		proj := dot(u[i], dU[i])
+		for k := range u[i] {
+			rows[i][k] = (dU[i][k] - u[i][k]*proj) / norms[i]
+		}
+	}
+	return dX
+}
+
+// zerosLike returns a zero matrix with the same shape as x.
+func zerosLike(x [][]float64) [][]float64 {
+	out := make([][]float64, len(x))
+	for i := range x {
+		out[i] = make([]float64, len(x[i]))
+	}
+	return out
+}
+
+// newMatrix returns a zero rows×cols tensor together with its row views, so
+// gradients can be filled in row by row.
+func newMatrix(rows, cols int) (*mathx.Tensor, [][]float64) {
+	t := mathx.NewTensor(rows, cols)
+	return t, t.Rows()
+}
+
+// validateBinaryTargets checks shapes and that every target lies in [0, 1].
+func validateBinaryTargets(predictions, yTrue *mathx.Tensor) error {
+	if err := validateTargets(predictions, yTrue); err != nil {
+		return err
+	}
+	for i, row := range yTrue.Rows() {
+		for j, y := range row {
+			if y < 0 || y > 1 || math.IsNaN(y) {
+				return fmt.Errorf("binary target %v at sample %d, column %d is outside [0, 1]", y, i, j)
+			}
 		}
-		sumLoss += -math.Log(p)
 	}
+	return nil
+}
 
-	meanLoss := sumLoss / float64(len(predictions))
-	return meanLoss, nil
+// clipProbability keeps p inside [ε, 1-ε] to prevent log(0).
+func clipProbability(p float64) float64 {
+	const epsilon = 1e-15
+	return math.Max(epsilon, math.Min(1-epsilon, p))
 }
 
-func (lf *LossFn) SoftmaxCrossEntropyBackward(predictions [][]float64, yTrue []int) [][]float64 {
-	samples := len(predictions)
-	dInputs := make([][]float64, samples)
-	for i := 0; i < samples; i++ {
-		dInputs[i] = make([]float64, len(predictions[i]))
-		copy(dInputs[i], predictions[i])
-		dInputs[i][yTrue[i]] -= 1.0
-		for j := range dInputs[i] {
-			dInputs[i][j] /= float64(samples)
+// stableSigmoid computes 1 / (1 + exp(-x)) without overflowing for large |x|.
+func stableSigmoid(x float64) float64 {
+	if x >= 0 {
+		return 1 / (1 + math.Exp(-x))
+	}
+	e := math.Exp(x)
+	return e / (1 + e)
+}
+
+// validateDistributions checks shapes and that each target row is a
+// probability distribution (non-negative, summing to 1).
+func validateDistributions(predictions, targets *mathx.Tensor) error {
+	if err := validateTargets(predictions, targets); err != nil {
+		return err
+	}
+	for i, row := range targets.Rows() {
+		var sum float64
+		for _, t := range row {
+			if t < 0 || math.IsNaN(t) {
+				return fmt.Errorf("target at sample %d has negative or NaN probability %v", i, t)
+			}
+			sum += t
+		}
+		if math.Abs(sum-1) > 1e-6 {
+			return fmt.Errorf("target at sample %d sums to %v, expected 1", i, sum)
 		}
 	}
+	return nil
+}
+
+// elementwiseGrad builds a gradient tensor by applying grad to each (prediction, target) pair.
+func elementwiseGrad(predictions, yTrue *mathx.Tensor, grad func(p, y float64) float64) *mathx.Tensor {
+	dInputs := mathx.NewTensor(predictions.Shape()...)
+	d, p, y := dInputs.Data(), predictions.Data(), yTrue.Data()
+	for i := range d {
+		d[i] = grad(p[i], y[i])
+	}
 	return dInputs
 }
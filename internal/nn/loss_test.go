@@ -0,0 +1,396 @@
+package nn
+
+import (
+	"math"
+	"testing"
+)
+
+// lossCase is a loss evaluated at fixed predictions and targets. The
+// predictions stay clear of kinks (p == y for MAE, |e| == δ for Huber, …) so
+// the central difference is accurate.
+type lossCase struct {
+	name   string
+	loss   Loss
+	pred   [][]float64
+	target Target
+}
+
+// checkLossGradients compares every loss's Backward with the central-difference
+// gradient of its Forward.
+func checkLossGradients(t *testing.T, tests []lossCase) {
+	t.Helper()
+	const h = 1e-6
+
+	for _, tt := range tests {
+		forward := func(x [][]float64) float64 {
+			loss, err := tt.loss.Forward(tensor(t, x), tt.target)
+			if err != nil {
+				t.Fatalf("%s: Forward() returned error: %v", tt.name, err)
+			}
+			return loss
+		}
+		grad, err := tt.loss.Backward(tensor(t, tt.pred), tt.target)
+		if err != nil {
+			t.Errorf("%s: Backward() returned error: %v", tt.name, err)
+			continue
+		}
+		got := grad.Rows()
+
+		x := copyMatrix(tt.pred)
+		for i := range x {
+			for j := range x[i] {
+				orig := x[i][j]
+				x[i][j] = orig + h
+				plus := forward(x)
+				x[i][j] = orig - h
+				minus := forward(x)
+				x[i][j] = orig
+				want := (plus - minus) / (2 * h)
+				if !almostEqual(got[i][j], want, 1e-6*math.Max(1, math.Abs(want))) {
+					t.Errorf("%s: grad[%d][%d] = %v; want %v", tt.name, i, j, got[i][j], want)
+				}
+			}
+		}
+	}
+}
+
+// checkLossValues compares every loss's Forward with a hand-computed value.
+func checkLossValues(t *testing.T, tests []lossCase, wants []float64) {
+	t.Helper()
+	for k, tt := range tests {
+		got, err := tt.loss.Forward(tensor(t, tt.pred), tt.target)
+		if err != nil {
+			t.Errorf("%s: Forward() returned error: %v", tt.name, err)
+			continue
+		}
+		if !almostEqual(got, wants[k], 1e-12) {
+			t.Errorf("%s: Forward() = %v; want %v", tt.name, got, wants[k])
+		}
+	}
+}
+
+// regressionPred and regressionTarget give errors of -0.7, 2.1, 0.4, -1.6,
+// 0.25 and -3.0, on both sides of the Huber delta of 1.
+var (
+	regressionPred   = [][]float64{{0.5, 2.3, -1.0}, {1.4, 0.75, -2.0}}
+	regressionTarget = [][]float64{{1.2, 0.2, -1.4}, {3.0, 0.5, 1.0}}
+)
+
+func TestRegressionLossGradients(t *testing.T) {
+	target := Target{Values: tensor(t, regressionTarget)}
+	checkLossGradients(t, []lossCase{
+		{"mse", MeanSquaredError{}, regressionPred, target},
+		{"mae", MeanAbsoluteError{}, regressionPred, target},
+		{"huber δ=1", Huber{Delta: 1}, regressionPred, target},
+		{"huber δ=0.5", Huber{Delta: 0.5}, regressionPred, target},
+		{"log-cosh", LogCosh{}, regressionPred, target},
+	})
+}
+
+func TestRegressionLossValues(t *testing.T) {
+	// Errors 1 and -3 on a 1×2 batch.
+	pred := [][]float64{{1, -1}}
+	target := Target{Values: tensor(t, [][]float64{{0, 2}})}
+	checkLossValues(t, []lossCase{
+		{"mse", MeanSquaredError{}, pred, target},
+		{"mae", MeanAbsoluteError{}, pred, target},
+		{"huber", Huber{Delta: 2}, pred, target},
+		{"log-cosh", LogCosh{}, pred, target},
+	}, []float64{
+		(1 + 9) / 2.0,
+		(1 + 3) / 2.0,
+		(0.5 + 2*(3-1)) / 2,
+		(math.Log(math.Cosh(1)) + math.Log(math.Cosh(3))) / 2,
+	})
+
+	if _, err := (MeanSquaredError{}).Forward(tensor(t, pred), Target{Values: tensor(t, [][]float64{{0}})}); err == nil {
+		t.Error("MSE Forward() expected error on shape mismatch, got nil")
+	}
+	if _, err := (Huber{}).Forward(tensor(t, pred), target); err == nil {
+		t.Error("Huber Forward() expected error for delta 0, got nil")
+	}
+}
+
+func TestBinaryCrossEntropyGradients(t *testing.T) {
+	multiHot, err := MultiHot([][]int{{0, 2}, {1}}, 3)
+	if err != nil {
+		t.Fatalf("MultiHot() returned error: %v", err)
+	}
+	soft := Target{Values: tensor(t, [][]float64{{0.2, 1, 0.7}, {0, 0.5, 1}})}
+	probs := [][]float64{{0.7, 0.2, 0.9}, {0.35, 0.6, 0.05}}
+	logits := [][]float64{{1.5, -0.4, 3.0}, {-2.2, 0.1, 0.8}}
+	checkLossGradients(t, []lossCase{
+		{"bce multi-label", BinaryCrossEntropy{}, probs, Target{Values: multiHot}},
+		{"bce soft targets", BinaryCrossEntropy{}, probs, soft},
+		{"bce logits multi-label", BinaryCrossEntropy{FromLogits: true}, logits, Target{Values: multiHot}},
+		{"bce logits soft targets", BinaryCrossEntropy{FromLogits: true}, logits, soft},
+	})
+}
+
+func TestBinaryCrossEntropyWithLogitsMatchesSigmoid(t *testing.T) {
+	logits := [][]float64{{1.5, -0.4, 30}, {-2.2, 0.1, -30}}
+	probs := copyMatrix(logits)
+	for i := range probs {
+		for j := range probs[i] {
+			probs[i][j] = stableSigmoid(probs[i][j])
+		}
+	}
+	y := tensor(t, [][]float64{{1, 0, 1}, {0, 1, 0}})
+
+	lf := LossFn{}
+	want, err := lf.BinaryCrossEntropy(tensor(t, probs), y)
+	if err != nil {
+		t.Fatalf("BinaryCrossEntropy() returned error: %v", err)
+	}
+	got, err := lf.BinaryCrossEntropyWithLogits(tensor(t, logits), y)
+	if err != nil {
+		t.Fatalf("BinaryCrossEntropyWithLogits() returned error: %v", err)
+	}
+	if !almostEqual(got, want, 1e-12) {
+		t.Errorf("BinaryCrossEntropyWithLogits() = %v; want %v", got, want)
+	}
+
+	// The fused gradient from Sigmoid outputs equals the gradient on logits.
+	fused, err := lf.SigmoidBinaryCrossEntropyBackward(tensor(t, probs), y)
+	if err != nil {
+		t.Fatalf("SigmoidBinaryCrossEntropyBackward() returned error: %v", err)
+	}
+	grad, err := lf.BinaryCrossEntropyWithLogitsBackward(tensor(t, logits), y)
+	if err != nil {
+		t.Fatalf("BinaryCrossEntropyWithLogitsBackward() returned error: %v", err)
+	}
+	g, f := grad.Rows(), fused.Rows()
+	for i := range g {
+		for j := range g[i] {
+			if !almostEqual(f[i][j], g[i][j], 1e-12) {
+				t.Errorf("SigmoidBinaryCrossEntropyBackward()[%d][%d] = %v; want %v", i, j, f[i][j], g[i][j])
+			}
+		}
+	}
+
+	if _, err := lf.BinaryCrossEntropy(tensor(t, probs), tensor(t, [][]float64{{1, 0, 2}, {0, 1, 0}})); err == nil {
+		t.Error("BinaryCrossEntropy() expected error for target outside [0, 1], got nil")
+	}
+	if _, err := MultiHot([][]int{{3}}, 3); err == nil {
+		t.Error("MultiHot() expected error for out-of-range class, got nil")
+	}
+}
+
+// Softmax outputs, logits and log-probabilities for a batch of three samples
+// over three classes.
+var (
+	classProbs    = [][]float64{{0.6, 0.3, 0.1}, {0.2, 0.5, 0.3}, {0.25, 0.15, 0.6}}
+	classLogits   = [][]float64{{1.2, -0.3, 0.5}, {0.1, 2.0, -1.1}, {-0.7, 0.4, 0.9}}
+	classLogProbs = [][]float64{{-0.5, -1.4, -2.3}, {-1.6, -0.7, -1.2}, {-1.4, -1.9, -0.5}}
+	classLabels   = []int{0, 2, 1}
+)
+
+func TestCrossEntropyGradients(t *testing.T) {
+	labels := Target{Labels: classLabels}
+	soft := Target{Values: tensor(t, [][]float64{{0.7, 0.2, 0.1}, {0, 0.4, 0.6}, {0.3, 0.3, 0.4}})}
+	classWeights := []float64{0.5, 2, 1}
+	sampleWeights := []float64{1, 0.25, 3}
+
+	checkLossGradients(t, []lossCase{
+		{"cce", CategoricalCrossEntropy{}, classProbs, labels},
+		{"cce class weights", CategoricalCrossEntropy{ClassWeights: classWeights}, classProbs, labels},
+		{"cce label smoothing", CategoricalCrossEntropy{LabelSmoothing: 0.2}, classProbs, labels},
+		{"cce soft targets", CategoricalCrossEntropy{}, classProbs, soft},
+		{"cce soft targets class weights", CategoricalCrossEntropy{ClassWeights: classWeights}, classProbs, soft},
+		{"cce logits", CategoricalCrossEntropy{FromLogits: true}, classLogits, labels},
+		{"cce logits weighted and smoothed",
+			CategoricalCrossEntropy{ClassWeights: classWeights, LabelSmoothing: 0.1, FromLogits: true}, classLogits, soft},
+		{"cce sample weights", CategoricalCrossEntropy{SampleWeights: sampleWeights}, classProbs, labels},
+		{"cce all options",
+			CategoricalCrossEntropy{ClassWeights: classWeights, SampleWeights: sampleWeights, LabelSmoothing: 0.3}, classProbs, soft},
+		{"cce logits sample weights",
+			CategoricalCrossEntropy{SampleWeights: sampleWeights, LabelSmoothing: 0.1, FromLogits: true}, classLogits, soft},
+		{"nll", NLLLoss{}, classLogProbs, labels},
+		{"nll class weights", NLLLoss{ClassWeights: classWeights}, classLogProbs, labels},
+		{"nll sample weights", NLLLoss{SampleWeights: sampleWeights}, classLogProbs, labels},
+	})
+}
+
+func TestCrossEntropyValues(t *testing.T) {
+	labels := Target{Labels: classLabels}
+	p0, p1, p2 := classProbs[0][0], classProbs[1][2], classProbs[2][1]
+	// Label smoothing 0.3 over 3 classes gives targets 0.8 on the true class
+	// and 0.1 elsewhere.
+	smoothed := func(row []float64, k int) float64 {
+		var loss float64
+		for j, p := range row {
+			t := 0.1
+			if j == k {
+				t = 0.8
+			}
+			loss -= t * math.Log(p)
+		}
+		return loss
+	}
+
+	checkLossValues(t, []lossCase{
+		{"cce", CategoricalCrossEntropy{}, classProbs, labels},
+		{"cce class weights", CategoricalCrossEntropy{ClassWeights: []float64{0.5, 2, 1}}, classProbs, labels},
+		{"cce label smoothing", CategoricalCrossEntropy{LabelSmoothing: 0.3}, classProbs, labels},
+		{"cce sample weights", CategoricalCrossEntropy{SampleWeights: []float64{1, 0, 3}}, classProbs, labels},
+		{"nll", NLLLoss{}, classLogProbs, labels},
+	}, []float64{
+		-(math.Log(p0) + math.Log(p1) + math.Log(p2)) / 3,
+		-(0.5*math.Log(p0) + 1*math.Log(p1) + 2*math.Log(p2)) / 3.5,
+		(smoothed(classProbs[0], 0) + smoothed(classProbs[1], 2) + smoothed(classProbs[2], 1)) / 3,
+		-(math.Log(p0) + 3*math.Log(p2)) / 4,
+		-(classLogProbs[0][0] + classLogProbs[1][2] + classLogProbs[2][1]) / 3,
+	})
+
+	// The fused logits path matches cross-entropy on the softmax outputs.
+	probs, err := NewActivationFn().Softmax(tensor(t, classLogits))
+	if err != nil {
+		t.Fatalf("Softmax() returned error: %v", err)
+	}
+	loss := CategoricalCrossEntropy{ClassWeights: []float64{0.5, 2, 1}, LabelSmoothing: 0.1}
+	want, err := loss.Forward(probs, labels)
+	if err != nil {
+		t.Fatalf("Forward() returned error: %v", err)
+	}
+	loss.FromLogits = true
+	got, err := loss.Forward(tensor(t, classLogits), labels)
+	if err != nil {
+		t.Fatalf("Forward() returned error: %v", err)
+	}
+	if !almostEqual(got, want, 1e-12) {
+		t.Errorf("Forward(logits) = %v; want %v from the softmax outputs", got, want)
+	}
+
+	lf := LossFn{}
+	for _, opts := range [][]CrossEntropyOption{
+		{WithClassWeights([]float64{1, 2})},
+		{WithSampleWeights([]float64{1, 1})},
+		{WithClassWeights([]float64{1, -1, 1})},
+		{WithSampleWeights([]float64{0, 0, 0})},
+		{WithLabelSmoothing(1)},
+		{WithLabelSmoothing(math.NaN())},
+	} {
+		if _, err := lf.CategoricalCrossEntropy(tensor(t, classProbs), classLabels, opts...); err == nil {
+			t.Errorf("CategoricalCrossEntropy() expected error for invalid options, got nil")
+		}
+	}
+}
+
+func TestFocalHingeKLGradients(t *testing.T) {
+	labels := Target{Labels: classLabels}
+	soft := Target{Values: tensor(t, [][]float64{{0.7, 0.2, 0.1}, {0, 0.4, 0.6}, {0.3, 0.3, 0.4}})}
+
+	// The hinge margins 1 + s_j - s_t on classLogits are at least 0.1 away
+	// from zero.
+	checkLossGradients(t, []lossCase{
+		{"focal", FocalLoss{Gamma: 2, Alpha: []float64{0.25, 1, 0.5}}, classProbs, labels},
+		{"focal γ=0.5", FocalLoss{Gamma: 0.5}, classProbs, labels},
+		{"focal logits", FocalLoss{Gamma: 2, Alpha: []float64{0.25, 1, 0.5}, FromLogits: true}, classLogits, labels},
+		{"hinge", MultiClassHinge{}, classLogits, labels},
+		{"squared hinge", MultiClassHinge{Squared: true}, classLogits, labels},
+		{"kl", KLDivergence{}, classProbs, soft},
+		{"kl logits", KLDivergence{FromLogits: true}, classLogits, soft},
+	})
+}
+
+func TestFocalHingeKLValues(t *testing.T) {
+	labels := Target{Labels: classLabels}
+	p0, p1, p2 := classProbs[0][0], classProbs[1][2], classProbs[2][1]
+	focal := func(p, alpha float64) float64 { return -alpha * (1 - p) * (1 - p) * math.Log(p) }
+	target := [][]float64{{0.5, 0.5, 0}, {0, 0.4, 0.6}, {0.3, 0.3, 0.4}}
+	var kl float64
+	for i := range target {
+		for k, v := range target[i] {
+			if v != 0 {
+				kl += v * math.Log(v/classProbs[i][k])
+			}
+		}
+	}
+
+	checkLossValues(t, []lossCase{
+		{"focal", FocalLoss{Gamma: 2, Alpha: []float64{0.25, 1, 0.5}}, classProbs, labels},
+		// Alpha weights samples by their true class, so moving the weight
+		// from class 0 to class 2 moves the loss onto sample 1.
+		{"focal weighting class 0", FocalLoss{Gamma: 2, Alpha: []float64{1, 0, 0}}, classProbs, labels},
+		{"focal weighting class 2", FocalLoss{Gamma: 2, Alpha: []float64{0, 0, 1}}, classProbs, labels},
+		{"focal γ=0 is cross-entropy", FocalLoss{Gamma: 0}, classProbs, labels},
+		{"hinge", MultiClassHinge{}, classLogits, labels},
+		{"squared hinge", MultiClassHinge{Squared: true}, classLogits, labels},
+		{"kl", KLDivergence{}, classProbs, Target{Values: tensor(t, target)}},
+		{"kl of identical distributions", KLDivergence{}, classProbs, Target{Values: tensor(t, classProbs)}},
+	}, []float64{
+		(focal(p0, 0.25) + focal(p1, 0.5) + focal(p2, 1)) / 3,
+		focal(p0, 1) / 3,
+		focal(p1, 1) / 3,
+		-(math.Log(p0) + math.Log(p1) + math.Log(p2)) / 3,
+		(0.3 + 2.2 + 4.1 + 1.5) / 3,
+		(0.3*0.3 + 2.2*2.2 + 4.1*4.1 + 1.5*1.5) / 3,
+		kl / 3,
+		0,
+	})
+
+	for _, loss := range []FocalLoss{
+		{Gamma: -1},
+		{Gamma: math.NaN()},
+		{Gamma: 2, Alpha: []float64{1, 1}},
+		{Gamma: 2, Alpha: []float64{1, -1, 1}},
+	} {
+		if _, err := loss.Forward(tensor(t, classProbs), labels); err == nil {
+			t.Errorf("FocalLoss%+v Forward() expected error, got nil", loss)
+		}
+	}
+	if _, err := (KLDivergence{}).Forward(tensor(t, classProbs), Target{Values: tensor(t, classLogits)}); err == nil {
+		t.Error("KLDivergence Forward() expected error for targets that are not distributions, got nil")
+	}
+}
+
+func TestMetricLossGradients(t *testing.T) {
+	// Three pairs: similar, dissimilar inside the margin of 1.5, and
+	// dissimilar beyond it.
+	pairs := [][]float64{{0.3, -0.2}, {1.0, 0.5}, {-1.0, 0.8}, {0.8, 0.4}, {0.4, 1.1}, {1.5, -0.9}}
+	// Hardest positives and negatives are unique for every anchor; with
+	// margin 0 the first anchor's triplet is inactive.
+	embeddings := [][]float64{{0.2, 0.1}, {0.9, -0.3}, {0.5, 0.7}, {1.1, 0.4}, {-0.9, 0.9}, {1.6, -0.2}}
+	groups := Target{Labels: []int{0, 0, 0, 1, 1, 1}}
+	views := [][]float64{{0.5, -1.0, 0.3}, {1.2, 0.4, -0.2}, {0.6, -0.7, 0.1}, {0.9, 0.8, -0.5}}
+
+	checkLossGradients(t, []lossCase{
+		{"contrastive", ContrastiveLoss{Margin: 1.5}, pairs, Target{Labels: []int{1, 0, 0}}},
+		{"triplet batch-hard", TripletBatchHard{Margin: 0.3}, embeddings, groups},
+		{"triplet batch-hard inactive anchor", TripletBatchHard{Margin: 0}, embeddings, groups},
+		{"nt-xent", NTXent{Temperature: 0.5}, views, Target{}},
+		{"info-nce", InfoNCE{Temperature: 0.2}, pairs, Target{}},
+	})
+}
+
+func TestMetricLossValues(t *testing.T) {
+	// Orthogonal views with τ = 1: cosine similarity ignores the norms, so
+	// every positive has similarity 1 and every negative 0.
+	orthogonal := [][]float64{{1, 0}, {0, 2}, {3, 0}, {0, 1}}
+
+	checkLossValues(t, []lossCase{
+		{"contrastive", ContrastiveLoss{Margin: 2}, [][]float64{{0, 0}, {0, 0}, {0.6, 0.8}, {0, 1.5}}, Target{Labels: []int{1, 0}}},
+		{"triplet batch-hard", TripletBatchHard{Margin: 1}, [][]float64{{0}, {1}, {3}, {5}}, Target{Labels: []int{0, 0, 1, 1}}},
+		{"nt-xent", NTXent{Temperature: 1}, orthogonal, Target{}},
+		{"nt-xent single pair", NTXent{Temperature: 0.1}, [][]float64{{1, 2}, {-3, 1}}, Target{}},
+		{"info-nce", InfoNCE{Temperature: 1}, orthogonal, Target{}},
+	}, []float64{
+		(1 + 0.5*0.5) / 2,
+		1.0 / 4,
+		math.Log(1 + 2/math.E),
+		0,
+		math.Log(1 + 1/math.E),
+	})
+
+	for _, tt := range []lossCase{
+		{"contrastive odd rows", ContrastiveLoss{Margin: 1}, [][]float64{{0}, {1}, {2}}, Target{Labels: []int{1}}},
+		{"contrastive bad label", ContrastiveLoss{Margin: 1}, [][]float64{{0}, {1}}, Target{Labels: []int{2}}},
+		{"triplet label count", TripletBatchHard{Margin: 1}, [][]float64{{0}, {1}}, Target{Labels: []int{0}}},
+		{"nt-xent temperature", NTXent{}, [][]float64{{1}, {2}}, Target{}},
+		{"info-nce odd rows", InfoNCE{Temperature: 1}, [][]float64{{1}, {2}, {3}}, Target{}},
+	} {
+		if _, err := tt.loss.Forward(tensor(t, tt.pred), tt.target); err == nil {
+			t.Errorf("%s: Forward() expected error, got nil", tt.name)
+		}
+	}
+}
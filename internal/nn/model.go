@@ -0,0 +1,136 @@
+package nn
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/SobhanYasami/nn-go/internal/mathx"
+)
+
+// Sequential is a linear stack of layers where each layer feeds the next.
+type Sequential struct {
+	Layers []Layer
+}
+
+// NewSequential creates a model from the given layers, in order.
+func NewSequential(layers ...Layer) *Sequential {
+	return &Sequential{Layers: layers}
+}
+
+// Add appends layers to the end of the model.
+func (s *Sequential) Add(layers ...Layer) {
+	s.Layers = append(s.Layers, layers...)
+}
+
+// Forward runs the batch through every layer and returns the final output.
+func (s *Sequential) Forward(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	if len(s.Layers) == 0 {
+		return nil, errors.New("model has no layers")
+	}
+
+	output := inputs
+	for i, layer := range s.Layers {
+		var err error
+		output, err = layer.Forward(output)
+		if err != nil {
+			return nil, fmt.Errorf("layer %d forward: %w", i, err)
+		}
+	}
+	return output, nil
+}
+
+// Backward propagates dOutputs from the last layer to the first, leaving
+// parameter gradients in each layer, and returns the gradient with respect
+// to the model inputs.
+func (s *Sequential) Backward(dOutputs *mathx.Tensor) (*mathx.Tensor, error) {
+	if len(s.Layers) == 0 {
+		return nil, errors.New("model has no layers")
+	}
+
+	dInputs := dOutputs
+	for i := len(s.Layers) - 1; i >= 0; i-- {
+		var err error
+		dInputs, err = s.Layers[i].Backward(dInputs)
+		if err != nil {
+			return nil, fmt.Errorf("layer %d backward: %w", i, err)
+		}
+	}
+	return dInputs, nil
+}
+
+// SetTraining switches every layer with distinct training and inference
+// behaviour, such as DropoutLayer, into the given mode.
+func (s *Sequential) SetTraining(training bool) {
+	for _, layer := range s.Layers {
+		if l, ok := layer.(interface{ SetTraining(bool) }); ok {
+			l.SetTraining(training)
+		}
+	}
+}
+
+// Predict runs a forward pass and returns the arg-max class of each sample.
+func (s *Sequential) Predict(inputs *mathx.Tensor) ([]int, error) {
+	output, err := s.Forward(inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := output.Rows()
+	predictions := make([]int, len(rows))
+	for i, row := range rows {
+		predictions[i] = argMax(row)
+	}
+	return predictions, nil
+}
+
+// Params returns the parameters of every layer, in layer order.
+func (s *Sequential) Params() [][]float64 {
+	var params [][]float64
+	for _, layer := range s.Layers {
+		params = append(params, layer.Params()...)
+	}
+	return params
+}
+
+// Grads returns the gradients of every layer, aligned with Params.
+func (s *Sequential) Grads() [][]float64 {
+	var grads [][]float64
+	for _, layer := range s.Layers {
+		grads = append(grads, layer.Grads()...)
+	}
+	return grads
+}
+
+// CloneParams returns a deep copy of a parameter list, e.g. to snapshot Sequential.Params.
+func CloneParams(params [][]float64) [][]float64 {
+	return copyMatrix(params)
+}
+
+// CopyParams copies src into dst element by element. Because dst keeps its
+// backing arrays, a model restored this way stays aligned with any optimizer
+// state built against it.
+func CopyParams(dst, src [][]float64) error {
+	if len(dst) != len(src) {
+		return fmt.Errorf("cannot copy %d parameter vectors into %d", len(src), len(dst))
+	}
+	for i := range dst {
+		if len(dst[i]) != len(src[i]) {
+			return fmt.Errorf("parameter %d has length %d, snapshot has %d", i, len(dst[i]), len(src[i]))
+		}
+	}
+	for i := range dst {
+		copy(dst[i], src[i])
+	}
+	return nil
+}
+
+// argMax returns the index of the largest value in a slice.
+func argMax(slice []float64) int {
+	best := 0
+	for i, v := range slice {
+		if v > slice[best] {
+			best = i
+		}
+	}
+	return best
+}
@@ -0,0 +1,127 @@
+package nn
+
+import (
+	"math"
+	"testing"
+)
+
+// twoLayerModel returns Dense(3→4) → Tanh → Dense(4→2) with fixed weights.
+func twoLayerModel(t *testing.T) (*Sequential, *DenseLayer, *DenseLayer) {
+	t.Helper()
+	dense1, err := NewDenseLayer(3, 4)
+	if err != nil {
+		t.Fatalf("NewDenseLayer() returned error: %v", err)
+	}
+	dense2, err := NewDenseLayer(4, 2)
+	if err != nil {
+		t.Fatalf("NewDenseLayer() returned error: %v", err)
+	}
+	for k, p := range append(dense1.Params(), dense2.Params()...) {
+		for j := range p {
+			p[j] = math.Sin(float64(7*k + 3*j + 1))
+		}
+	}
+	return NewSequential(dense1, NewActivationLayer(Tanh), dense2), dense1, dense2
+}
+
+var modelInputs = [][]float64{{0.5, -1.2, 2.0}, {-0.3, 0.8, 0.1}, {1.5, 0.2, -0.7}}
+
+func TestSequentialForwardComposesLayers(t *testing.T) {
+	model, dense1, dense2 := twoLayerModel(t)
+	out, err := model.Forward(tensor(t, modelInputs))
+	if err != nil {
+		t.Fatalf("Forward() returned error: %v", err)
+	}
+	got := out.Rows()
+
+	// dense computes x · Wᵀ + b from the layer's parameter rows.
+	dense := func(layer *DenseLayer, x []float64) []float64 {
+		params := layer.Params()
+		weights, biases := params[:len(params)-1], params[len(params)-1]
+		y := make([]float64, len(weights))
+		for n, w := range weights {
+			y[n] = biases[n]
+			for j := range x {
+				y[n] += w[j] * x[j]
+			}
+		}
+		return y
+	}
+	for i, x := range modelInputs {
+		hidden := dense(dense1, x)
+		for n := range hidden {
+			hidden[n] = math.Tanh(hidden[n])
+		}
+		want := dense(dense2, hidden)
+		for n := range want {
+			if !almostEqual(got[i][n], want[n], 1e-12) {
+				t.Errorf("output[%d][%d] = %v; want %v", i, n, got[i][n], want[n])
+			}
+		}
+	}
+
+	predictions, err := model.Predict(tensor(t, modelInputs))
+	if err != nil {
+		t.Fatalf("Predict() returned error: %v", err)
+	}
+	for i, row := range got {
+		if predictions[i] != argMax(row) {
+			t.Errorf("Predict()[%d] = %d; want %d", i, predictions[i], argMax(row))
+		}
+	}
+}
+
+func TestSequentialBackwardMatchesNumericalGradient(t *testing.T) {
+	model, _, _ := twoLayerModel(t)
+	upstream := [][]float64{{0.4, -1.1}, {2.0, 0.3}, {-0.8, 0.6}}
+	checkLayerGradients(t, "sequential", model, modelInputs, upstream)
+}
+
+func TestSequentialParamsAliasLayerStorage(t *testing.T) {
+	model, dense1, dense2 := twoLayerModel(t)
+	params := model.Params()
+	// Four weight rows and the biases of dense1, then two rows and the biases of dense2.
+	if len(params) != 4+1+2+1 {
+		t.Fatalf("len(Params()) = %d; want 8", len(params))
+	}
+
+	params[0][1] = 42
+	params[4][2] = 43
+	params[7][0] = 44
+	if got := dense1.Weights.At(0, 1); got != 42 {
+		t.Errorf("dense1 weight[0][1] = %v after writing through Params; want 42", got)
+	}
+	if got := dense1.Biases[2]; got != 43 {
+		t.Errorf("dense1 bias[2] = %v after writing through Params; want 43", got)
+	}
+	if got := dense2.Biases[0]; got != 44 {
+		t.Errorf("dense2 bias[0] = %v after writing through Params; want 44", got)
+	}
+
+	// Grads keeps pointing at the buffers Backward writes into.
+	grads := model.Grads()
+	if _, err := model.Forward(tensor(t, modelInputs)); err != nil {
+		t.Fatalf("Forward() returned error: %v", err)
+	}
+	if _, err := model.Backward(tensor(t, [][]float64{{1, 0}, {0, 1}, {1, 1}})); err != nil {
+		t.Fatalf("Backward() returned error: %v", err)
+	}
+	if grads[0][0] != dense1.DWeights.At(0, 0) || grads[0][0] == 0 {
+		t.Errorf("Grads()[0][0] = %v; want the updated dense1 gradient %v", grads[0][0], dense1.DWeights.At(0, 0))
+	}
+}
+
+func TestSequentialErrors(t *testing.T) {
+	if _, err := NewSequential().Forward(tensor(t, modelInputs)); err == nil {
+		t.Error("Forward() on an empty model expected error, got nil")
+	}
+	model, _, _ := twoLayerModel(t)
+	if _, err := model.Forward(tensor(t, [][]float64{{1, 2}})); err == nil {
+		t.Error("Forward() expected error on feature mismatch, got nil")
+	}
+
+	model.Add(NewActivationLayer(Softmax))
+	if len(model.Layers) != 4 {
+		t.Errorf("len(Layers) = %d after Add; want 4", len(model.Layers))
+	}
+}
@@ -0,0 +1,655 @@
+package nn
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/SobhanYasami/nn-go/internal/mathx"
+)
+
+// Target is the ground truth for a batch. Classification and metric losses
+// read Labels; regression, multi-label and distribution losses read Values.
+type Target struct {
+	Labels []int
+	Values *mathx.Tensor
+}
+
+// Loss is a differentiable training objective.
+//
+// Forward returns the mean loss of a batch and Backward returns dLoss/dPred,
+// ready to be passed to the last layer's Backward. Losses on probabilities
+// return gradients with respect to the probabilities, so a trailing Softmax
+// layer applies its own Jacobian; losses with a FromLogits option instead take
+// raw logits and fuse the softmax, which is cheaper and more stable.
+type Loss interface {
+	Forward(pred *mathx.Tensor, target Target) (float64, error)
+	Backward(pred *mathx.Tensor, target Target) (*mathx.Tensor, error)
+}
+
+//? ------------------------------
+//? Classification Losses
+//? ------------------------------
+
+// CategoricalCrossEntropy is categorical cross-entropy. It uses Target.Labels
+// when set and otherwise treats Target.Values as soft target distributions.
+// With FromLogits, pred holds raw logits instead of softmax outputs.
+// SampleWeights, when set, must have one weight per sample of the batch.
+type CategoricalCrossEntropy struct {
+	ClassWeights   []float64
+	SampleWeights  []float64
+	LabelSmoothing float64
+	FromLogits     bool
+}
+
+// options converts the fields into CrossEntropyOptions.
+func (l CategoricalCrossEntropy) options() []CrossEntropyOption {
+	var opts []CrossEntropyOption
+	if l.ClassWeights != nil {
+		opts = append(opts, WithClassWeights(l.ClassWeights))
+	}
+	if l.SampleWeights != nil {
+		opts = append(opts, WithSampleWeights(l.SampleWeights))
+	}
+	if l.LabelSmoothing != 0 {
+		opts = append(opts, WithLabelSmoothing(l.LabelSmoothing))
+	}
+	return opts
+}
+
+// Forward implements Loss.
+func (l CategoricalCrossEntropy) Forward(pred *mathx.Tensor, target Target) (float64, error) {
+	lf := LossFn{}
+	if l.FromLogits {
+		targets, err := labelTargets(pred, target)
+		if err != nil {
+			return 0, err
+		}
+		return lf.CategoricalCrossEntropyWithLogits(pred, targets, l.options()...)
+	}
+	if target.Labels != nil {
+		return lf.CategoricalCrossEntropy(pred, target.Labels, l.options()...)
+	}
+	return lf.CategoricalCrossEntropySoft(pred, target.Values, l.options()...)
+}
+
+// Backward implements Loss.
+func (l CategoricalCrossEntropy) Backward(pred *mathx.Tensor, target Target) (*mathx.Tensor, error) {
+	lf := LossFn{}
+	if l.FromLogits {
+		targets, err := labelTargets(pred, target)
+		if err != nil {
+			return nil, err
+		}
+		return lf.CategoricalCrossEntropyWithLogitsBackward(pred, targets, l.options()...)
+	}
+	if target.Labels != nil {
+		return lf.CategoricalCrossEntropyBackward(pred, target.Labels, l.options()...)
+	}
+	return lf.CategoricalCrossEntropySoftBackward(pred, target.Values, l.options()...)
+}
+
+// labelTargets returns Target.Values, or one-hot rows built from Target.Labels when set.
+func labelTargets(pred *mathx.Tensor, target Target) (*mathx.Tensor, error) {
+	if target.Labels == nil {
+		return target.Values, nil
+	}
+	if err := validateLabels(pred, target.Labels); err != nil {
+		return nil, err
+	}
+	return mathx.FromMatrix(oneHot(target.Labels, pred.Dim(1)))
+}
+
+// NLLLoss is the negative log-likelihood of Target.Labels given
+// log-probabilities, e.g. from a LogSoftmax layer.
+type NLLLoss struct {
+	ClassWeights  []float64
+	SampleWeights []float64
+}
+
+// Forward implements Loss.
+func (l NLLLoss) Forward(pred *mathx.Tensor, target Target) (float64, error) {
+	lf := LossFn{}
+	return lf.NLLLoss(pred, target.Labels, CategoricalCrossEntropy{ClassWeights: l.ClassWeights, SampleWeights: l.SampleWeights}.options()...)
+}
+
+// Backward implements Loss.
+func (l NLLLoss) Backward(pred *mathx.Tensor, target Target) (*mathx.Tensor, error) {
+	lf := LossFn{}
+	return lf.NLLLossBackward(pred, target.Labels, CategoricalCrossEntropy{ClassWeights: l.ClassWeights, SampleWeights: l.SampleWeights}.options()...)
+}
+
+// FocalLoss is the multi-class focal loss on softmax outputs, or on raw
+// logits when FromLogits is set.
+type FocalLoss struct {
+	Gamma float64
+	// Alpha holds one balancing weight per class; nil weights every class by 1.
+	Alpha      []float64
+	FromLogits bool
+}
+
+// Forward implements Loss.
+func (l FocalLoss) Forward(pred *mathx.Tensor, target Target) (float64, error) {
+	probs, err := softmaxIf(l.FromLogits, pred)
+	if err != nil {
+		return 0, err
+	}
+	lf := LossFn{}
+	return lf.FocalLoss(probs, target.Labels, l.Gamma, l.Alpha)
+}
+
+// Backward implements Loss.
+func (l FocalLoss) Backward(pred *mathx.Tensor, target Target) (*mathx.Tensor, error) {
+	lf := LossFn{}
+	if l.FromLogits {
+		probs, err := softmaxIf(true, pred)
+		if err != nil {
+			return nil, err
+		}
+		return lf.SoftmaxFocalBackward(probs, target.Labels, l.Gamma, l.Alpha)
+	}
+	return lf.FocalLossBackward(pred, target.Labels, l.Gamma, l.Alpha)
+}
+
+// softmaxIf returns softmax(pred) when fromLogits is set and pred otherwise.
+func softmaxIf(fromLogits bool, pred *mathx.Tensor) (*mathx.Tensor, error) {
+	if !fromLogits {
+		return pred, nil
+	}
+	return NewActivationFn().Softmax(pred)
+}
+
+// MultiClassHinge is the (optionally squared) multi-class hinge loss on raw scores.
+type MultiClassHinge struct {
+	Squared bool
+}
+
+// Forward implements Loss.
+func (l MultiClassHinge) Forward(pred *mathx.Tensor, target Target) (float64, error) {
+	lf := LossFn{}
+	return lf.MultiClassHinge(pred, target.Labels, l.Squared)
+}
+
+// Backward implements Loss.
+func (l MultiClassHinge) Backward(pred *mathx.Tensor, target Target) (*mathx.Tensor, error) {
+	lf := LossFn{}
+	return lf.MultiClassHingeBackward(pred, target.Labels, l.Squared)
+}
+
+// KLDivergence is KL(target ‖ prediction) against Target.Values, on softmax
+// outputs or, when FromLogits is set, on raw logits.
+type KLDivergence struct {
+	FromLogits bool
+}
+
+// Forward implements Loss.
+func (l KLDivergence) Forward(pred *mathx.Tensor, target Target) (float64, error) {
+	probs, err := softmaxIf(l.FromLogits, pred)
+	if err != nil {
+		return 0, err
+	}
+	lf := LossFn{}
+	return lf.KLDivergence(probs, target.Values)
+}
+
+// Backward implements Loss.
+func (l KLDivergence) Backward(pred *mathx.Tensor, target Target) (*mathx.Tensor, error) {
+	lf := LossFn{}
+	if l.FromLogits {
+		probs, err := softmaxIf(true, pred)
+		if err != nil {
+			return nil, err
+		}
+		return lf.SoftmaxKLDivergenceBackward(probs, target.Values)
+	}
+	return lf.KLDivergenceBackward(pred, target.Values)
+}
+
+//? ------------------------------
+//? Regression & Binary Losses
+//? ------------------------------
+
+// MeanSquaredError is the MSE loss against Target.Values.
+type MeanSquaredError struct{}
+
+// Forward implements Loss.
+func (MeanSquaredError) Forward(pred *mathx.Tensor, target Target) (float64, error) {
+	lf := LossFn{}
+	return lf.MeanSquaredError(pred, target.Values)
+}
+
+// Backward implements Loss.
+func (MeanSquaredError) Backward(pred *mathx.Tensor, target Target) (*mathx.Tensor, error) {
+	lf := LossFn{}
+	return lf.MeanSquaredErrorBackward(pred, target.Values)
+}
+
+// MeanAbsoluteError is the MAE loss against Target.Values.
+type MeanAbsoluteError struct{}
+
+// Forward implements Loss.
+func (MeanAbsoluteError) Forward(pred *mathx.Tensor, target Target) (float64, error) {
+	lf := LossFn{}
+	return lf.MeanAbsoluteError(pred, target.Values)
+}
+
+// Backward implements Loss.
+func (MeanAbsoluteError) Backward(pred *mathx.Tensor, target Target) (*mathx.Tensor, error) {
+	lf := LossFn{}
+	return lf.MeanAbsoluteErrorBackward(pred, target.Values)
+}
+
+// Huber is the Huber loss against Target.Values.
+type Huber struct {
+	Delta float64
+}
+
+// Forward implements Loss.
+func (l Huber) Forward(pred *mathx.Tensor, target Target) (float64, error) {
+	lf := LossFn{}
+	return lf.Huber(pred, target.Values, l.Delta)
+}
+
+// Backward implements Loss.
+func (l Huber) Backward(pred *mathx.Tensor, target Target) (*mathx.Tensor, error) {
+	lf := LossFn{}
+	return lf.HuberBackward(pred, target.Values, l.Delta)
+}
+
+// LogCosh is the log-cosh loss against Target.Values.
+type LogCosh struct{}
+
+// Forward implements Loss.
+func (LogCosh) Forward(pred *mathx.Tensor, target Target) (float64, error) {
+	lf := LossFn{}
+	return lf.LogCosh(pred, target.Values)
+}
+
+// Backward implements Loss.
+func (LogCosh) Backward(pred *mathx.Tensor, target Target) (*mathx.Tensor, error) {
+	lf := LossFn{}
+	return lf.LogCoshBackward(pred, target.Values)
+}
+
+// BinaryCrossEntropy is binary / multi-label cross-entropy against
+// Target.Values. With FromLogits set, pred holds raw logits and the sigmoid
+// is fused; otherwise pred holds probabilities and the gradient is taken with
+// respect to them.
+type BinaryCrossEntropy struct {
+	FromLogits bool
+}
+
+// Forward implements Loss.
+func (l BinaryCrossEntropy) Forward(pred *mathx.Tensor, target Target) (float64, error) {
+	lf := LossFn{}
+	if l.FromLogits {
+		return lf.BinaryCrossEntropyWithLogits(pred, target.Values)
+	}
+	return lf.BinaryCrossEntropy(pred, target.Values)
+}
+
+// Backward implements Loss.
+func (l BinaryCrossEntropy) Backward(pred *mathx.Tensor, target Target) (*mathx.Tensor, error) {
+	lf := LossFn{}
+	if l.FromLogits {
+		return lf.BinaryCrossEntropyWithLogitsBackward(pred, target.Values)
+	}
+	return lf.BinaryCrossEntropyBackward(pred, target.Values)
+}
+
+//? ------------------------------
+//? Metric-Learning Losses
+//? ------------------------------
+
+// ContrastiveLoss is the pairwise contrastive loss. pred stacks both sides of
+// N pairs (rows 0..N-1 against rows N..2N-1) and Target.Labels holds N pair
+// labels, 1 for similar and 0 for dissimilar.
+type ContrastiveLoss struct {
+	Margin float64
+}
+
+// split separates the stacked pairs and converts the pair labels.
+func (l ContrastiveLoss) split(pred *mathx.Tensor, target Target) (*mathx.Tensor, *mathx.Tensor, []bool, error) {
+	if err := validateEmbeddings(pred); err != nil {
+		return nil, nil, nil, err
+	}
+	if pred.Dim(0)%2 != 0 || len(target.Labels) != pred.Dim(0)/2 {
+		return nil, nil, nil, fmt.Errorf("contrastive loss needs 2N stacked embeddings and N pair labels, got %d and %d", pred.Dim(0), len(target.Labels))
+	}
+	similar := make([]bool, len(target.Labels))
+	for i, y := range target.Labels {
+		if y != 0 && y != 1 {
+			return nil, nil, nil, fmt.Errorf("pair label must be 0 or 1, got %d at pair %d", y, i)
+		}
+		similar[i] = y == 1
+	}
+	x1, x2 := halves(pred)
+	return x1, x2, similar, nil
+}
+
+// Forward implements Loss.
+func (l ContrastiveLoss) Forward(pred *mathx.Tensor, target Target) (float64, error) {
+	x1, x2, similar, err := l.split(pred, target)
+	if err != nil {
+		return 0, err
+	}
+	lf := LossFn{}
+	return lf.ContrastiveLoss(x1, x2, similar, l.Margin)
+}
+
+// Backward implements Loss.
+func (l ContrastiveLoss) Backward(pred *mathx.Tensor, target Target) (*mathx.Tensor, error) {
+	x1, x2, similar, err := l.split(pred, target)
+	if err != nil {
+		return nil, err
+	}
+	lf := LossFn{}
+	dX1, dX2, err := lf.ContrastiveLossBackward(x1, x2, similar, l.Margin)
+	if err != nil {
+		return nil, err
+	}
+	return stack(dX1, dX2), nil
+}
+
+// TripletBatchHard is the batch-hard triplet loss over embeddings with Target.Labels.
+type TripletBatchHard struct {
+	Margin float64
+}
+
+// Forward implements Loss.
+func (l TripletBatchHard) Forward(pred *mathx.Tensor, target Target) (float64, error) {
+	lf := LossFn{}
+	return lf.TripletBatchHard(pred, target.Labels, l.Margin)
+}
+
+// Backward implements Loss.
+func (l TripletBatchHard) Backward(pred *mathx.Tensor, target Target) (*mathx.Tensor, error) {
+	lf := LossFn{}
+	return lf.TripletBatchHardBackward(pred, target.Labels, l.Margin)
+}
+
+// NTXent is the NT-Xent loss over 2N stacked views; it needs no target.
+type NTXent struct {
+	Temperature float64
+}
+
+// Forward implements Loss.
+func (l NTXent) Forward(pred *mathx.Tensor, _ Target) (float64, error) {
+	lf := LossFn{}
+	return lf.NTXent(pred, l.Temperature)
+}
+
+// Backward implements Loss.
+func (l NTXent) Backward(pred *mathx.Tensor, _ Target) (*mathx.Tensor, error) {
+	lf := LossFn{}
+	return lf.NTXentBackward(pred, l.Temperature)
+}
+
+// InfoNCE is the InfoNCE loss. pred stacks N queries followed by their N
+// positive keys; it needs no target.
+type InfoNCE struct {
+	Temperature float64
+}
+
+// Forward implements Loss.
+func (l InfoNCE) Forward(pred *mathx.Tensor, _ Target) (float64, error) {
+	if err := l.validate(pred); err != nil {
+		return 0, err
+	}
+	queries, keys := halves(pred)
+	lf := LossFn{}
+	return lf.InfoNCE(queries, keys, l.Temperature)
+}
+
+// Backward implements Loss.
+func (l InfoNCE) Backward(pred *mathx.Tensor, _ Target) (*mathx.Tensor, error) {
+	if err := l.validate(pred); err != nil {
+		return nil, err
+	}
+	queries, keys := halves(pred)
+	lf := LossFn{}
+	dQ, dK, err := lf.InfoNCEBackward(queries, keys, l.Temperature)
+	if err != nil {
+		return nil, err
+	}
+	return stack(dQ, dK), nil
+}
+
+// validate checks that pred stacks an equal number of queries and keys.
+func (l InfoNCE) validate(pred *mathx.Tensor) error {
+	if err := validateEmbeddings(pred); err != nil {
+		return err
+	}
+	if pred.Dim(0)%2 != 0 {
+		return fmt.Errorf("InfoNCE needs N queries stacked on N keys, got %d rows", pred.Dim(0))
+	}
+	return nil
+}
+
+// halves splits x into views of its first and second half of rows.
+func halves(x *mathx.Tensor) (*mathx.Tensor, *mathx.Tensor) {
+	half := x.Dim(0) / 2
+	top, _ := x.Slice(0, 0, half)
+	bottom, _ := x.Slice(0, half, x.Dim(0))
+	return top, bottom
+}
+
+// stack concatenates two gradients of the same shape along the batch axis.
+func stack(a, b *mathx.Tensor) *mathx.Tensor {
+	shape := a.Shape()
+	shape[0] += b.Dim(0)
+	out := mathx.NewTensor(shape...)
+	top, bottom := halves(out)
+	top.CopyFrom(a)
+	bottom.CopyFrom(b)
+	return out
+}
+
+//? ------------------------------
+//? Loss Registry
+//? ------------------------------
+
+// LossFactory builds a loss from numeric hyper-parameters, e.g. read from a
+// config file. Missing parameters take the loss's defaults.
+type LossFactory func(params map[string]float64) (Loss, error)
+
+var (
+	lossRegistryMu sync.RWMutex
+	lossRegistry   = map[string]LossFactory{}
+)
+
+// RegisterLoss makes a loss available to NewLoss under name.
+// It fails if the name is empty or already registered.
+func RegisterLoss(name string, factory LossFactory) error {
+	if name == "" || factory == nil {
+		return errors.New("loss name and factory are required")
+	}
+	lossRegistryMu.Lock()
+	defer lossRegistryMu.Unlock()
+	if _, ok := lossRegistry[name]; ok {
+		return fmt.Errorf("loss %q is already registered", name)
+	}
+	lossRegistry[name] = factory
+	return nil
+}
+
+// NewLoss builds the loss registered under name.
+func NewLoss(name string, params map[string]float64) (Loss, error) {
+	lossRegistryMu.RLock()
+	factory, ok := lossRegistry[name]
+	lossRegistryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown loss %q", name)
+	}
+	loss, err := factory(params)
+	if err != nil {
+		return nil, fmt.Errorf("loss %q: %w", name, err)
+	}
+	return loss, nil
+}
+
+// LossNames returns the registered loss names in sorted order.
+func LossNames() []string {
+	lossRegistryMu.RLock()
+	defer lossRegistryMu.RUnlock()
+	names := make([]string, 0, len(lossRegistry))
+	for name := range lossRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lossParams merges params over defaults and rejects unknown keys.
+func lossParams(params, defaults map[string]float64) (map[string]float64, error) {
+	merged := make(map[string]float64, len(defaults))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range params {
+		if _, ok := defaults[k]; !ok {
+			return nil, fmt.Errorf("unknown parameter %q", k)
+		}
+		merged[k] = v
+	}
+	return merged, nil
+}
+
+// positiveParam returns an error unless params[key] is positive.
+func positiveParam(params map[string]float64, key string) error {
+	if !(params[key] > 0) {
+		return fmt.Errorf("%s must be positive, got %v", key, params[key])
+	}
+	return nil
+}
+
+// boolParam reads a 0/1 flag from params.
+func boolParam(params map[string]float64, key string) (bool, error) {
+	switch params[key] {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	}
+	return false, fmt.Errorf("%s must be 0 or 1, got %v", key, params[key])
+}
+
+// builtinLosses are registered at package initialization.
+var builtinLosses = map[string]LossFactory{
+	"categorical_crossentropy": func(params map[string]float64) (Loss, error) {
+		p, err := lossParams(params, map[string]float64{"label_smoothing": 0, "from_logits": 0})
+		if err != nil {
+			return nil, err
+		}
+		if eps := p["label_smoothing"]; !(eps >= 0 && eps < 1) {
+			return nil, fmt.Errorf("label_smoothing must be in [0, 1), got %v", eps)
+		}
+		fromLogits, err := boolParam(p, "from_logits")
+		if err != nil {
+			return nil, err
+		}
+		return CategoricalCrossEntropy{LabelSmoothing: p["label_smoothing"], FromLogits: fromLogits}, nil
+	},
+	"nll": noParams(NLLLoss{}),
+	"focal": func(params map[string]float64) (Loss, error) {
+		p, err := lossParams(params, map[string]float64{"gamma": 2, "from_logits": 0})
+		if err != nil {
+			return nil, err
+		}
+		if err := validateFocalParams(p["gamma"], nil, 0); err != nil {
+			return nil, err
+		}
+		fromLogits, err := boolParam(p, "from_logits")
+		if err != nil {
+			return nil, err
+		}
+		return FocalLoss{Gamma: p["gamma"], FromLogits: fromLogits}, nil
+	},
+	"hinge":         noParams(MultiClassHinge{}),
+	"squared_hinge": noParams(MultiClassHinge{Squared: true}),
+	"kl_divergence": func(params map[string]float64) (Loss, error) {
+		p, err := lossParams(params, map[string]float64{"from_logits": 0})
+		if err != nil {
+			return nil, err
+		}
+		fromLogits, err := boolParam(p, "from_logits")
+		if err != nil {
+			return nil, err
+		}
+		return KLDivergence{FromLogits: fromLogits}, nil
+	},
+	"mse": noParams(MeanSquaredError{}),
+	"mae": noParams(MeanAbsoluteError{}),
+	"huber": func(params map[string]float64) (Loss, error) {
+		p, err := lossParams(params, map[string]float64{"delta": 1})
+		if err != nil {
+			return nil, err
+		}
+		if err := positiveParam(p, "delta"); err != nil {
+			return nil, err
+		}
+		return Huber{Delta: p["delta"]}, nil
+	},
+	"log_cosh":                   noParams(LogCosh{}),
+	"binary_crossentropy":        noParams(BinaryCrossEntropy{}),
+	"binary_crossentropy_logits": noParams(BinaryCrossEntropy{FromLogits: true}),
+	"contrastive": func(params map[string]float64) (Loss, error) {
+		p, err := lossParams(params, map[string]float64{"margin": 1})
+		if err != nil {
+			return nil, err
+		}
+		if err := positiveParam(p, "margin"); err != nil {
+			return nil, err
+		}
+		return ContrastiveLoss{Margin: p["margin"]}, nil
+	},
+	"triplet_batch_hard": func(params map[string]float64) (Loss, error) {
+		p, err := lossParams(params, map[string]float64{"margin": 1})
+		if err != nil {
+			return nil, err
+		}
+		if err := positiveParam(p, "margin"); err != nil {
+			return nil, err
+		}
+		return TripletBatchHard{Margin: p["margin"]}, nil
+	},
+	"nt_xent": func(params map[string]float64) (Loss, error) {
+		p, err := lossParams(params, map[string]float64{"temperature": 0.5})
+		if err != nil {
+			return nil, err
+		}
+		if err := positiveParam(p, "temperature"); err != nil {
+			return nil, err
+		}
+		return NTXent{Temperature: p["temperature"]}, nil
+	},
+	"info_nce": func(params map[string]float64) (Loss, error) {
+		p, err := lossParams(params, map[string]float64{"temperature": 0.1})
+		if err != nil {
+			return nil, err
+		}
+		if err := positiveParam(p, "temperature"); err != nil {
+			return nil, err
+		}
+		return InfoNCE{Temperature: p["temperature"]}, nil
+	},
+}
+
+// noParams returns a factory for a loss without hyper-parameters.
+func noParams(loss Loss) LossFactory {
+	return func(params map[string]float64) (Loss, error) {
+		if _, err := lossParams(params, nil); err != nil {
+			return nil, err
+		}
+		return loss, nil
+	}
+}
+
+func init() {
+	for name, factory := range builtinLosses {
+		if err := RegisterLoss(name, factory); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package nn
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestNewLoss(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]float64
+		want   Loss
+	}{
+		{"mse", nil, MeanSquaredError{}},
+		{"huber", nil, Huber{Delta: 1}},
+		{"huber", map[string]float64{"delta": 2}, Huber{Delta: 2}},
+		{"categorical_crossentropy", map[string]float64{"label_smoothing": 0.1, "from_logits": 1},
+			CategoricalCrossEntropy{LabelSmoothing: 0.1, FromLogits: true}},
+		{"focal", map[string]float64{"gamma": 1}, FocalLoss{Gamma: 1}},
+		{"squared_hinge", nil, MultiClassHinge{Squared: true}},
+		{"kl_divergence", map[string]float64{"from_logits": 1}, KLDivergence{FromLogits: true}},
+		{"binary_crossentropy_logits", nil, BinaryCrossEntropy{FromLogits: true}},
+		{"triplet_batch_hard", map[string]float64{"margin": 0.2}, TripletBatchHard{Margin: 0.2}},
+		{"nt_xent", nil, NTXent{Temperature: 0.5}},
+	}
+
+	for _, tt := range tests {
+		got, err := NewLoss(tt.name, tt.params)
+		if err != nil {
+			t.Errorf("NewLoss(%q, %v) returned error: %v", tt.name, tt.params, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("NewLoss(%q, %v) = %#v; want %#v", tt.name, tt.params, got, tt.want)
+		}
+	}
+}
+
+func TestNewLossErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]float64
+		wantErr string
+	}{
+		{"cross_entropy", nil, `unknown loss "cross_entropy"`},
+		{"mse", map[string]float64{"delta": 1}, `unknown parameter "delta"`},
+		{"huber", map[string]float64{"delta": 0}, "delta must be positive"},
+		{"huber", map[string]float64{"delta": math.NaN()}, "delta must be positive"},
+		{"nt_xent", map[string]float64{"temperature": math.NaN()}, "temperature must be positive"},
+		{"categorical_crossentropy", map[string]float64{"label_smoothing": 1}, "label_smoothing"},
+		{"categorical_crossentropy", map[string]float64{"label_smoothing": math.NaN()}, "label_smoothing"},
+		{"focal", map[string]float64{"gamma": math.NaN()}, "gamma must be non-negative"},
+		{"kl_divergence", map[string]float64{"from_logits": 2}, "from_logits must be 0 or 1"},
+	}
+
+	for _, tt := range tests {
+		_, err := NewLoss(tt.name, tt.params)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("NewLoss(%q, %v) error = %v; want %q", tt.name, tt.params, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRegisterLoss(t *testing.T) {
+	factory := func(map[string]float64) (Loss, error) { return MeanAbsoluteError{}, nil }
+
+	if err := RegisterLoss("mse", factory); err == nil {
+		t.Error(`RegisterLoss("mse") expected error for a duplicate name, got nil`)
+	}
+	if err := RegisterLoss("", factory); err == nil {
+		t.Error(`RegisterLoss("") expected error, got nil`)
+	}
+	if err := RegisterLoss("test_nil_factory", nil); err == nil {
+		t.Error("RegisterLoss() expected error for a nil factory, got nil")
+	}
+
+	// The failed duplicate left the builtin in place.
+	if loss, err := NewLoss("mse", nil); err != nil || loss != (MeanSquaredError{}) {
+		t.Errorf(`NewLoss("mse") = %v, %v; want MeanSquaredError{}`, loss, err)
+	}
+
+	if err := RegisterLoss("test_custom", factory); err != nil {
+		t.Fatalf("RegisterLoss() returned error: %v", err)
+	}
+	t.Cleanup(func() {
+		lossRegistryMu.Lock()
+		delete(lossRegistry, "test_custom")
+		lossRegistryMu.Unlock()
+	})
+	if loss, err := NewLoss("test_custom", nil); err != nil || loss != (MeanAbsoluteError{}) {
+		t.Errorf(`NewLoss("test_custom") = %v, %v; want MeanAbsoluteError{}`, loss, err)
+	}
+
+	names := LossNames()
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("LossNames() = %v; want sorted names", names)
+	}
+	for _, name := range []string{"mse", "info_nce", "test_custom"} {
+		if i := sort.SearchStrings(names, name); i == len(names) || names[i] != name {
+			t.Errorf("LossNames() = %v; missing %q", names, name)
+		}
+	}
+}
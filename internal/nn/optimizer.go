@@ -0,0 +1,479 @@
+package nn
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Optimizer updates parameters in place from their gradients.
+//
+// Params and grads are the aligned vector lists produced by Layer.Params and
+// Layer.Grads. Optimizers keep their per-parameter state by position in that
+// list, so the same model must be passed on every call.
+type Optimizer interface {
+	// Step applies one update to params using grads.
+	Step(params, grads [][]float64) error
+	// LearningRate returns the current step size.
+	LearningRate() float64
+	// SetLearningRate changes the step size used by subsequent steps.
+	SetLearningRate(lr float64)
+}
+
+// OptimizerState is a snapshot of an optimizer's hyper-state and
+// per-parameter buffers, used for checkpointing.
+type OptimizerState struct {
+	Name    string
+	LR      float64
+	Step    int
+	Buffers map[string][][]float64
+}
+
+// StatefulOptimizer is implemented by optimizers whose internal state can be
+// saved and restored. All optimizers in this package implement it.
+type StatefulOptimizer interface {
+	Optimizer
+	State() OptimizerState
+	LoadState(state OptimizerState) error
+}
+
+//? ------------------------------
+//? SGD
+//? ------------------------------
+
+// SGD implements stochastic gradient descent with optional (Nesterov) momentum.
+//
+//	v := μ·v + g
+//	p := p - lr·v             (classical momentum)
+//	p := p - lr·(g + μ·v)     (Nesterov)
+type SGD struct {
+	LR       float64
+	Momentum float64
+	Nesterov bool
+
+	velocity [][]float64
+}
+
+// NewSGD creates an SGD optimizer. Use momentum 0 for plain gradient descent.
+func NewSGD(lr, momentum float64, nesterov bool) (*SGD, error) {
+	if err := validateLearningRate(lr); err != nil {
+		return nil, err
+	}
+	if momentum < 0 || momentum >= 1 {
+		return nil, fmt.Errorf("momentum must be in [0, 1), got %v", momentum)
+	}
+	if nesterov && momentum == 0 {
+		return nil, errors.New("nesterov momentum requires momentum > 0")
+	}
+	return &SGD{LR: lr, Momentum: momentum, Nesterov: nesterov}, nil
+}
+
+// Step applies one SGD update.
+func (o *SGD) Step(params, grads [][]float64) error {
+	if err := validateParamsGrads(params, grads); err != nil {
+		return err
+	}
+
+	if o.Momentum == 0 {
+		for i := range params {
+			for j, g := range grads[i] {
+				params[i][j] -= o.LR * g
+			}
+		}
+		return nil
+	}
+
+	var err error
+	if o.velocity, err = ensureState(o.velocity, params); err != nil {
+		return err
+	}
+	for i := range params {
+		v := o.velocity[i]
+		for j, g := range grads[i] {
+			v[j] = o.Momentum*v[j] + g
+			if o.Nesterov {
+				params[i][j] -= o.LR * (g + o.Momentum*v[j])
+			} else {
+				params[i][j] -= o.LR * v[j]
+			}
+		}
+	}
+	return nil
+}
+
+// LearningRate returns the current learning rate.
+func (o *SGD) LearningRate() float64 { return o.LR }
+
+// SetLearningRate sets the learning rate.
+func (o *SGD) SetLearningRate(lr float64) { o.LR = lr }
+
+// State returns a deep copy of the velocity buffers.
+func (o *SGD) State() OptimizerState {
+	return OptimizerState{Name: "sgd", LR: o.LR, Buffers: map[string][][]float64{"velocity": copyMatrix(o.velocity)}}
+}
+
+// LoadState restores a state produced by State.
+func (o *SGD) LoadState(state OptimizerState) error {
+	if err := checkStateName(state, "sgd"); err != nil {
+		return err
+	}
+	o.LR = state.LR
+	o.velocity = copyMatrix(state.Buffers["velocity"])
+	return nil
+}
+
+//? ------------------------------
+//? Adam / AdamW
+//? ------------------------------
+
+// Adam implements adaptive moment estimation with bias correction.
+//
+//	m := β1·m + (1-β1)·g
+//	v := β2·v + (1-β2)·g²
+//	p := p - lr·m̂ / (√v̂ + ε)
+type Adam struct {
+	LR      float64
+	Beta1   float64
+	Beta2   float64
+	Epsilon float64
+
+	step int
+	m    [][]float64
+	v    [][]float64
+}
+
+// NewAdam creates an Adam optimizer. Typical values are beta1=0.9, beta2=0.999, epsilon=1e-8.
+func NewAdam(lr, beta1, beta2, epsilon float64) (*Adam, error) {
+	if err := validateLearningRate(lr); err != nil {
+		return nil, err
+	}
+	if err := validateDecay("beta1", beta1); err != nil {
+		return nil, err
+	}
+	if err := validateDecay("beta2", beta2); err != nil {
+		return nil, err
+	}
+	if epsilon <= 0 {
+		return nil, fmt.Errorf("epsilon must be positive, got %v", epsilon)
+	}
+	return &Adam{LR: lr, Beta1: beta1, Beta2: beta2, Epsilon: epsilon}, nil
+}
+
+// Step applies one Adam update.
+func (o *Adam) Step(params, grads [][]float64) error {
+	if err := validateParamsGrads(params, grads); err != nil {
+		return err
+	}
+
+	var err error
+	if o.m, err = ensureState(o.m, params); err != nil {
+		return err
+	}
+	if o.v, err = ensureState(o.v, params); err != nil {
+		return err
+	}
+	o.step++
+
+	correction1 := 1 - math.Pow(o.Beta1, float64(o.step))
+	correction2 := 1 - math.Pow(o.Beta2, float64(o.step))
+
+	for i := range params {
+		m, v := o.m[i], o.v[i]
+		for j, g := range grads[i] {
+			m[j] = o.Beta1*m[j] + (1-o.Beta1)*g
+			v[j] = o.Beta2*v[j] + (1-o.Beta2)*g*g
+			mHat := m[j] / correction1
+			vHat := v[j] / correction2
+			params[i][j] -= o.LR * mHat / (math.Sqrt(vHat) + o.Epsilon)
+		}
+	}
+	return nil
+}
+
+// LearningRate returns the current learning rate.
+func (o *Adam) LearningRate() float64 { return o.LR }
+
+// SetLearningRate sets the learning rate.
+func (o *Adam) SetLearningRate(lr float64) { o.LR = lr }
+
+// State returns the step count and deep copies of the moment estimates.
+func (o *Adam) State() OptimizerState {
+	return o.state("adam")
+}
+
+// LoadState restores a state produced by State.
+func (o *Adam) LoadState(state OptimizerState) error {
+	return o.loadState(state, "adam")
+}
+
+// state snapshots Adam's buffers under the given optimizer name.
+func (o *Adam) state(name string) OptimizerState {
+	return OptimizerState{
+		Name: name,
+		LR:   o.LR,
+		Step: o.step,
+		Buffers: map[string][][]float64{
+			"m": copyMatrix(o.m),
+			"v": copyMatrix(o.v),
+		},
+	}
+}
+
+// loadState restores Adam's buffers from a state saved under name.
+func (o *Adam) loadState(state OptimizerState, name string) error {
+	if err := checkStateName(state, name); err != nil {
+		return err
+	}
+	o.LR = state.LR
+	o.step = state.Step
+	o.m = copyMatrix(state.Buffers["m"])
+	o.v = copyMatrix(state.Buffers["v"])
+	return nil
+}
+
+// AdamW is Adam with decoupled weight decay: parameters are shrunk by
+// lr·weightDecay·p before the adaptive update instead of adding L2 to the gradient.
+type AdamW struct {
+	Adam
+	WeightDecay float64
+}
+
+// NewAdamW creates an AdamW optimizer.
+func NewAdamW(lr, beta1, beta2, epsilon, weightDecay float64) (*AdamW, error) {
+	adam, err := NewAdam(lr, beta1, beta2, epsilon)
+	if err != nil {
+		return nil, err
+	}
+	if weightDecay < 0 {
+		return nil, fmt.Errorf("weight decay must be non-negative, got %v", weightDecay)
+	}
+	return &AdamW{Adam: *adam, WeightDecay: weightDecay}, nil
+}
+
+// Step applies weight decay followed by one Adam update.
+func (o *AdamW) Step(params, grads [][]float64) error {
+	if err := validateParamsGrads(params, grads); err != nil {
+		return err
+	}
+
+	shrink := 1 - o.LR*o.WeightDecay
+	for i := range params {
+		for j := range params[i] {
+			params[i][j] *= shrink
+		}
+	}
+	return o.Adam.Step(params, grads)
+}
+
+// State returns the step count and deep copies of the moment estimates.
+func (o *AdamW) State() OptimizerState {
+	return o.state("adamw")
+}
+
+// LoadState restores a state produced by State.
+func (o *AdamW) LoadState(state OptimizerState) error {
+	return o.loadState(state, "adamw")
+}
+
+//? ------------------------------
+//? RMSProp
+//? ------------------------------
+
+// RMSProp scales each update by a running average of squared gradients.
+//
+//	s := ρ·s + (1-ρ)·g²
+//	p := p - lr·g / (√s + ε)
+type RMSProp struct {
+	LR      float64
+	Rho     float64
+	Epsilon float64
+
+	sqAvg [][]float64
+}
+
+// NewRMSProp creates an RMSProp optimizer. Typical values are rho=0.9, epsilon=1e-8.
+func NewRMSProp(lr, rho, epsilon float64) (*RMSProp, error) {
+	if err := validateLearningRate(lr); err != nil {
+		return nil, err
+	}
+	if err := validateDecay("rho", rho); err != nil {
+		return nil, err
+	}
+	if epsilon <= 0 {
+		return nil, fmt.Errorf("epsilon must be positive, got %v", epsilon)
+	}
+	return &RMSProp{LR: lr, Rho: rho, Epsilon: epsilon}, nil
+}
+
+// Step applies one RMSProp update.
+func (o *RMSProp) Step(params, grads [][]float64) error {
+	if err := validateParamsGrads(params, grads); err != nil {
+		return err
+	}
+
+	var err error
+	if o.sqAvg, err = ensureState(o.sqAvg, params); err != nil {
+		return err
+	}
+	for i := range params {
+		s := o.sqAvg[i]
+		for j, g := range grads[i] {
+			s[j] = o.Rho*s[j] + (1-o.Rho)*g*g
+			params[i][j] -= o.LR * g / (math.Sqrt(s[j]) + o.Epsilon)
+		}
+	}
+	return nil
+}
+
+// LearningRate returns the current learning rate.
+func (o *RMSProp) LearningRate() float64 { return o.LR }
+
+// SetLearningRate sets the learning rate.
+func (o *RMSProp) SetLearningRate(lr float64) { o.LR = lr }
+
+// State returns a deep copy of the squared-gradient averages.
+func (o *RMSProp) State() OptimizerState {
+	return OptimizerState{Name: "rmsprop", LR: o.LR, Buffers: map[string][][]float64{"sq_avg": copyMatrix(o.sqAvg)}}
+}
+
+// LoadState restores a state produced by State.
+func (o *RMSProp) LoadState(state OptimizerState) error {
+	if err := checkStateName(state, "rmsprop"); err != nil {
+		return err
+	}
+	o.LR = state.LR
+	o.sqAvg = copyMatrix(state.Buffers["sq_avg"])
+	return nil
+}
+
+//? ------------------------------
+//? Adagrad
+//? ------------------------------
+
+// Adagrad scales each update by the accumulated sum of squared gradients.
+//
+//	s := s + g²
+//	p := p - lr·g / (√s + ε)
+type Adagrad struct {
+	LR      float64
+	Epsilon float64
+
+	sqSum [][]float64
+}
+
+// NewAdagrad creates an Adagrad optimizer. A typical epsilon is 1e-10.
+func NewAdagrad(lr, epsilon float64) (*Adagrad, error) {
+	if err := validateLearningRate(lr); err != nil {
+		return nil, err
+	}
+	if epsilon <= 0 {
+		return nil, fmt.Errorf("epsilon must be positive, got %v", epsilon)
+	}
+	return &Adagrad{LR: lr, Epsilon: epsilon}, nil
+}
+
+// Step applies one Adagrad update.
+func (o *Adagrad) Step(params, grads [][]float64) error {
+	if err := validateParamsGrads(params, grads); err != nil {
+		return err
+	}
+
+	var err error
+	if o.sqSum, err = ensureState(o.sqSum, params); err != nil {
+		return err
+	}
+	for i := range params {
+		s := o.sqSum[i]
+		for j, g := range grads[i] {
+			s[j] += g * g
+			params[i][j] -= o.LR * g / (math.Sqrt(s[j]) + o.Epsilon)
+		}
+	}
+	return nil
+}
+
+// LearningRate returns the current learning rate.
+func (o *Adagrad) LearningRate() float64 { return o.LR }
+
+// SetLearningRate sets the learning rate.
+func (o *Adagrad) SetLearningRate(lr float64) { o.LR = lr }
+
+// State returns a deep copy of the accumulated squared gradients.
+func (o *Adagrad) State() OptimizerState {
+	return OptimizerState{Name: "adagrad", LR: o.LR, Buffers: map[string][][]float64{"sq_sum": copyMatrix(o.sqSum)}}
+}
+
+// LoadState restores a state produced by State.
+func (o *Adagrad) LoadState(state OptimizerState) error {
+	if err := checkStateName(state, "adagrad"); err != nil {
+		return err
+	}
+	o.LR = state.LR
+	o.sqSum = copyMatrix(state.Buffers["sq_sum"])
+	return nil
+}
+
+//? ------------------------------
+//? Utility Functions
+//? ------------------------------
+
+// validateParamsGrads checks that params and grads are aligned vector lists.
+func validateParamsGrads(params, grads [][]float64) error {
+	if len(params) != len(grads) {
+		return fmt.Errorf("got %d parameter vectors but %d gradient vectors", len(params), len(grads))
+	}
+	for i := range params {
+		if len(params[i]) != len(grads[i]) {
+			return fmt.Errorf("parameter %d has length %d but gradient has length %d", i, len(params[i]), len(grads[i]))
+		}
+	}
+	return nil
+}
+
+// validateLearningRate checks that a learning rate is positive and finite.
+func validateLearningRate(lr float64) error {
+	if lr <= 0 || math.IsInf(lr, 0) || math.IsNaN(lr) {
+		return fmt.Errorf("learning rate must be positive, got %v", lr)
+	}
+	return nil
+}
+
+// validateDecay checks that a moving-average coefficient lies in [0, 1).
+func validateDecay(name string, value float64) error {
+	if value < 0 || value >= 1 {
+		return fmt.Errorf("%s must be in [0, 1), got %v", name, value)
+	}
+	return nil
+}
+
+// checkStateName rejects a state saved by a different optimizer type.
+func checkStateName(state OptimizerState, want string) error {
+	if state.Name != want {
+		return fmt.Errorf("cannot load %q optimizer state into %s", state.Name, want)
+	}
+	return nil
+}
+
+// ensureState returns state unchanged if it matches the shape of params and
+// a freshly zeroed buffer if it is empty. A non-empty buffer of another shape,
+// e.g. restored by LoadState from a different model's checkpoint, is an error
+// rather than being silently reset.
+func ensureState(state, params [][]float64) ([][]float64, error) {
+	if len(state) == 0 {
+		state = make([][]float64, len(params))
+		for i := range params {
+			state[i] = make([]float64, len(params[i]))
+		}
+		return state, nil
+	}
+
+	if len(state) != len(params) {
+		return nil, fmt.Errorf("optimizer state has %d vectors but there are %d parameter vectors", len(state), len(params))
+	}
+	for i := range params {
+		if len(state[i]) != len(params[i]) {
+			return nil, fmt.Errorf("optimizer state %d has length %d but parameter %d has length %d", i, len(state[i]), i, len(params[i]))
+		}
+	}
+	return state, nil
+}
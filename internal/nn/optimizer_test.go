@@ -0,0 +1,192 @@
+package nn
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestOptimizerSteps(t *testing.T) {
+	must := func(o Optimizer, err error) Optimizer {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("optimizer constructor returned error: %v", err)
+		}
+		return o
+	}
+	// Every optimizer starts from p = [1, -2] and takes two steps; wants[k]
+	// holds the parameters after step k+1, worked out by hand from the update
+	// rules in optimizer.go.
+	same := [][]float64{{0.5, -1}}
+	flipped := [][]float64{{-0.5, 1}}
+	tests := []struct {
+		name  string
+		opt   Optimizer
+		grads [2][][]float64
+		wants [2][]float64
+	}{
+		{
+			// p -= 0.1·g
+			name:  "sgd",
+			opt:   must(NewSGD(0.1, 0, false)),
+			grads: [2][][]float64{same, same},
+			wants: [2][]float64{{0.95, -1.9}, {0.9, -1.8}},
+		},
+		{
+			// v = g, then v = 0.9·g + g = 1.9·g
+			name:  "momentum",
+			opt:   must(NewSGD(0.1, 0.9, false)),
+			grads: [2][][]float64{same, same},
+			wants: [2][]float64{{0.95, -1.9}, {0.855, -1.71}},
+		},
+		{
+			// p -= 0.1·(g + 0.9·v): 1.9·g on the first step, 2.71·g on the second
+			name:  "nesterov",
+			opt:   must(NewSGD(0.1, 0.9, true)),
+			grads: [2][][]float64{same, same},
+			wants: [2][]float64{{0.905, -1.81}, {0.7695, -1.539}},
+		},
+		{
+			// Bias correction makes m̂ = g and v̂ = g² on step 1, so the step
+			// is lr·sign(g). On step 2, m̂ = ∓0.005/0.19 = ∓1/38 and v̂ = g²,
+			// so the step is 1/190 against the new gradient.
+			name:  "adam",
+			opt:   must(NewAdam(0.1, 0.9, 0.999, 1e-8)),
+			grads: [2][][]float64{same, flipped},
+			wants: [2][]float64{{0.9, -1.9}, {0.9 + 1.0/190, -1.9 - 1.0/190}},
+		},
+		{
+			// Decoupled decay shrinks p by 1 - 0.1·0.1 = 0.99 before the
+			// Adam step, which is unchanged by the decay.
+			name:  "adamw",
+			opt:   must(NewAdamW(0.1, 0.9, 0.999, 1e-8, 0.1)),
+			grads: [2][][]float64{same, flipped},
+			wants: [2][]float64{{0.89, -1.88}, {0.8811 + 1.0/190, -1.8612 - 1.0/190}},
+		},
+		{
+			// s = 0.1·g² then 0.19·g², and p -= 0.01·g/√s
+			name:  "rmsprop",
+			opt:   must(NewRMSProp(0.01, 0.9, 1e-8)),
+			grads: [2][][]float64{same, same},
+			wants: [2][]float64{
+				{1 - 0.01/math.Sqrt(0.1), -2 + 0.01/math.Sqrt(0.1)},
+				{1 - 0.01/math.Sqrt(0.1) - 0.01/math.Sqrt(0.19), -2 + 0.01/math.Sqrt(0.1) + 0.01/math.Sqrt(0.19)},
+			},
+		},
+		{
+			// s = g² then 2·g², so the steps are lr·sign(g) and lr·sign(g)/√2
+			name:  "adagrad",
+			opt:   must(NewAdagrad(0.1, 1e-10)),
+			grads: [2][][]float64{same, same},
+			wants: [2][]float64{{0.9, -1.9}, {0.9 - 0.1/math.Sqrt2, -1.9 + 0.1/math.Sqrt2}},
+		},
+	}
+
+	for _, tt := range tests {
+		params := [][]float64{{1, -2}}
+		for step, grads := range tt.grads {
+			if err := tt.opt.Step(params, grads); err != nil {
+				t.Fatalf("%s: Step() returned error: %v", tt.name, err)
+			}
+			for j, want := range tt.wants[step] {
+				if !almostEqual(params[0][j], want, 1e-7) {
+					t.Errorf("%s: step %d param[%d] = %v; want %v", tt.name, step+1, j, params[0][j], want)
+				}
+			}
+		}
+	}
+}
+
+func TestAdamWDecaysWithoutGradient(t *testing.T) {
+	adamW, err := NewAdamW(0.1, 0.9, 0.999, 1e-8, 0.5)
+	if err != nil {
+		t.Fatalf("NewAdamW() returned error: %v", err)
+	}
+	// With a zero gradient the Adam step vanishes and only the decoupled
+	// decay remains: p *= 1 - 0.1·0.5.
+	params := [][]float64{{2, -4}}
+	if err := adamW.Step(params, [][]float64{{0, 0}}); err != nil {
+		t.Fatalf("Step() returned error: %v", err)
+	}
+	if params[0][0] != 1.9 || params[0][1] != -3.8 {
+		t.Errorf("AdamW zero-gradient step = %v; want [1.9 -3.8]", params[0])
+	}
+}
+
+func TestOptimizerRejectsMismatchedGrads(t *testing.T) {
+	sgd, err := NewSGD(0.1, 0, false)
+	if err != nil {
+		t.Fatalf("NewSGD() returned error: %v", err)
+	}
+	if err := sgd.Step([][]float64{{1, 2}}, [][]float64{{1}}); err == nil {
+		t.Error("Step() expected error on mismatched gradient length, got nil")
+	}
+}
+
+// statefulOptimizers builds one of each optimizer, fresh on every call.
+func statefulOptimizers(t *testing.T) map[string]StatefulOptimizer {
+	t.Helper()
+	sgd, err1 := NewSGD(0.1, 0.9, true)
+	adam, err2 := NewAdam(0.1, 0.9, 0.999, 1e-8)
+	adamW, err3 := NewAdamW(0.1, 0.9, 0.999, 1e-8, 0.1)
+	rmsProp, err4 := NewRMSProp(0.01, 0.9, 1e-8)
+	adagrad, err5 := NewAdagrad(0.1, 1e-10)
+	if err := errors.Join(err1, err2, err3, err4, err5); err != nil {
+		t.Fatalf("optimizer constructor returned error: %v", err)
+	}
+	return map[string]StatefulOptimizer{"sgd": sgd, "adam": adam, "adamw": adamW, "rmsprop": rmsProp, "adagrad": adagrad}
+}
+
+func TestOptimizerStateRoundTrip(t *testing.T) {
+	grads := [][][]float64{{{0.5, -1}}, {{-0.2, 0.3}}, {{0.4, 0.1}}}
+	straight, first, resumed := statefulOptimizers(t), statefulOptimizers(t), statefulOptimizers(t)
+
+	for name := range straight {
+		want := [][]float64{{1, -2}}
+		for _, g := range grads {
+			if err := straight[name].Step(want, g); err != nil {
+				t.Fatalf("%s: Step() returned error: %v", name, err)
+			}
+		}
+
+		// Take the first step, then continue with a fresh optimizer.
+		got := [][]float64{{1, -2}}
+		if err := first[name].Step(got, grads[0]); err != nil {
+			t.Fatalf("%s: Step() returned error: %v", name, err)
+		}
+		if err := resumed[name].LoadState(first[name].State()); err != nil {
+			t.Fatalf("%s: LoadState() returned error: %v", name, err)
+		}
+		for _, g := range grads[1:] {
+			if err := resumed[name].Step(got, g); err != nil {
+				t.Fatalf("%s: Step() returned error: %v", name, err)
+			}
+		}
+		for j := range want[0] {
+			if got[0][j] != want[0][j] {
+				t.Errorf("%s: param[%d] = %v after resuming; want %v", name, j, got[0][j], want[0][j])
+			}
+		}
+	}
+}
+
+func TestOptimizerRejectsMismatchedState(t *testing.T) {
+	trained, fresh := statefulOptimizers(t), statefulOptimizers(t)
+	for name, opt := range trained {
+		if err := opt.Step([][]float64{{1, -2}}, [][]float64{{0.5, -1}}); err != nil {
+			t.Fatalf("%s: Step() returned error: %v", name, err)
+		}
+		if err := fresh[name].LoadState(opt.State()); err != nil {
+			t.Fatalf("%s: LoadState() returned error: %v", name, err)
+		}
+		// The loaded buffers belong to a model with a single 2-vector.
+		if err := fresh[name].Step([][]float64{{1, -2, 3}}, [][]float64{{0.5, -1, 1}}); err == nil {
+			t.Errorf("%s: Step() after loading state of another shape expected error, got nil", name)
+		}
+	}
+
+	sgd, adam := statefulOptimizers(t)["sgd"], statefulOptimizers(t)["adam"]
+	if err := adam.LoadState(sgd.State()); err == nil {
+		t.Error("Adam LoadState(SGD state) expected error, got nil")
+	}
+}
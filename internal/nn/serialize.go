@@ -0,0 +1,494 @@
+package nn
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/SobhanYasami/nn-go/internal/mathx"
+)
+
+// Model files start with a versioned header and carry a CRC-32 (IEEE)
+// checksum of their layer data.
+//
+// Binary layout (little endian):
+//
+//	magic "NNGO" | version uint16 | payload length uint32 | payload | crc32 uint32
+//
+// JSON layout:
+//
+//	{"format": "nn-go-model", "version": 1, "checksum": "…", "layers": [...]}
+const (
+	modelMagic      = "NNGO"
+	modelFormatName = "nn-go-model"
+	modelVersion    = 1
+
+	// maxModelPayload guards against allocating absurd buffers for corrupt headers.
+	maxModelPayload = 1 << 30
+)
+
+// layerSpec is the encoding-independent description of one layer: its type,
+// scalar configuration and the vectors returned by Layer.Params.
+type layerSpec struct {
+	Type       string             `json:"type"`
+	Activation ActivationType     `json:"activation,omitempty"`
+	Config     map[string]float64 `json:"config,omitempty"`
+	Params     [][]float64        `json:"params,omitempty"`
+}
+
+// modelJSON is the top-level JSON document.
+type modelJSON struct {
+	Format   string          `json:"format"`
+	Version  int             `json:"version"`
+	Checksum string          `json:"checksum"`
+	Layers   json.RawMessage `json:"layers"`
+}
+
+// Save writes the model in the compact binary format.
+func (s *Sequential) Save(w io.Writer) error {
+	specs, err := s.layerSpecs()
+	if err != nil {
+		return err
+	}
+
+	var payload bytes.Buffer
+	if err := encodeLayerSpecs(&payload, specs); err != nil {
+		return err
+	}
+	if payload.Len() > maxModelPayload {
+		return fmt.Errorf("model payload of %d bytes exceeds format limit", payload.Len())
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(modelMagic)
+	binary.Write(&buf, binary.LittleEndian, uint16(modelVersion))
+	binary.Write(&buf, binary.LittleEndian, uint32(payload.Len()))
+	buf.Write(payload.Bytes())
+	binary.Write(&buf, binary.LittleEndian, crc32.ChecksumIEEE(payload.Bytes()))
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// SaveJSON writes the model as indented, human-readable JSON.
+func (s *Sequential) SaveJSON(w io.Writer) error {
+	specs, err := s.layerSpecs()
+	if err != nil {
+		return err
+	}
+
+	layers, err := json.Marshal(specs)
+	if err != nil {
+		return fmt.Errorf("encoding layers: %w", err)
+	}
+	doc := modelJSON{
+		Format:   modelFormatName,
+		Version:  modelVersion,
+		Checksum: fmt.Sprintf("%08x", crc32.ChecksumIEEE(layers)),
+		Layers:   layers,
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// Load reads a model written by Save or SaveJSON; the format is detected
+// from the first bytes of r.
+func Load(r io.Reader) (*Sequential, error) {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(len(modelMagic))
+	if err != nil {
+		return nil, fmt.Errorf("reading model header: %w", err)
+	}
+
+	var specs []layerSpec
+	if string(head) == modelMagic {
+		specs, err = decodeBinaryModel(br)
+	} else {
+		specs, err = decodeJSONModel(br)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	model := NewSequential()
+	for i, spec := range specs {
+		layer, err := layerFromSpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("layer %d: %w", i, err)
+		}
+		model.Add(layer)
+	}
+	return model, nil
+}
+
+//? ------------------------------
+//? Layer <-> Spec
+//? ------------------------------
+
+// layerSpecs describes every layer of the model.
+func (s *Sequential) layerSpecs() ([]layerSpec, error) {
+	specs := make([]layerSpec, len(s.Layers))
+	for i, layer := range s.Layers {
+		spec, err := specFromLayer(layer)
+		if err != nil {
+			return nil, fmt.Errorf("layer %d: %w", i, err)
+		}
+		specs[i] = spec
+	}
+	return specs, nil
+}
+
+// specFromLayer captures the architecture and parameters of a known layer type.
+func specFromLayer(layer Layer) (layerSpec, error) {
+	switch l := layer.(type) {
+	case *DenseLayer:
+		if l.Weights == nil || l.Weights.Size() == 0 {
+			return layerSpec{}, errors.New("dense layer has no weights")
+		}
+		return layerSpec{
+			Type: "dense",
+			Config: map[string]float64{
+				"inputs":  float64(l.Weights.Dim(1)),
+				"outputs": float64(l.Weights.Dim(0)),
+			},
+			Params: CloneParams(l.Params()),
+		}, nil
+	case *ActivationLayer:
+		// Only the parameters the activation reads are saved, and they are
+		// checked the way layerFromSpec checks them on load.
+		spec := layerSpec{Type: "activation", Activation: l.Activation, Config: map[string]float64{}}
+		if l.Activation == LeakyReLU || l.Activation == ELU {
+			if err := validateAlpha(l.Activation, l.Alpha); err != nil {
+				return layerSpec{}, err
+			}
+			spec.Config["alpha"] = l.Alpha
+		}
+		if l.Activation == Softmax && l.Temperature != 0 {
+			if err := validateTemperature(l.Temperature); err != nil {
+				return layerSpec{}, err
+			}
+			spec.Config["temperature"] = l.Temperature
+		}
+		return spec, nil
+	case *PReLULayer:
+		return layerSpec{
+			Type:   "prelu",
+			Config: map[string]float64{"features": float64(len(l.Alpha))},
+			Params: CloneParams(l.Params()),
+		}, nil
+	case *SwishLayer:
+		return layerSpec{
+			Type:   "swish",
+			Config: map[string]float64{"features": float64(len(l.Beta))},
+			Params: CloneParams(l.Params()),
+		}, nil
+	case *DropoutLayer:
+		return layerSpec{
+			Type:   "dropout",
+			Config: map[string]float64{"rate": l.Rate},
+		}, nil
+	default:
+		return layerSpec{}, fmt.Errorf("cannot serialize layer of type %T", layer)
+	}
+}
+
+// layerFromSpec rebuilds a layer and loads its parameters.
+func layerFromSpec(spec layerSpec) (Layer, error) {
+	var layer Layer
+	switch spec.Type {
+	case "dense":
+		inputs, err := specInt(spec, "inputs")
+		if err != nil {
+			return nil, err
+		}
+		outputs, err := specInt(spec, "outputs")
+		if err != nil {
+			return nil, err
+		}
+		dense, err := NewDenseLayer(inputs, outputs)
+		if err != nil {
+			return nil, err
+		}
+		layer = dense
+	case "activation":
+		if _, err := NewActivationFn().Apply(spec.Activation, mathx.NewTensor(1, 1), false); err != nil {
+			return nil, err
+		}
+		if alpha, ok := spec.Config["alpha"]; ok {
+			activation, err := NewActivationLayerWithAlpha(spec.Activation, alpha)
+			if err != nil {
+				return nil, err
+			}
+			layer = activation
+		} else {
+			layer = NewActivationLayer(spec.Activation)
+		}
+		if temperature, ok := spec.Config["temperature"]; ok {
+			if spec.Activation != Softmax {
+				return nil, fmt.Errorf("%s activation has no temperature", spec.Activation)
+			}
+			if err := validateTemperature(temperature); err != nil {
+				return nil, err
+			}
+			layer.(*ActivationLayer).Temperature = temperature
+		}
+	case "prelu":
+		features, err := specInt(spec, "features")
+		if err != nil {
+			return nil, err
+		}
+		prelu, err := NewPReLULayer(features, DefaultLeakyReLUAlpha)
+		if err != nil {
+			return nil, err
+		}
+		layer = prelu
+	case "swish":
+		features, err := specInt(spec, "features")
+		if err != nil {
+			return nil, err
+		}
+		swish, err := NewSwishLayer(features, 1)
+		if err != nil {
+			return nil, err
+		}
+		layer = swish
+	case "dropout":
+		rate, ok := spec.Config["rate"]
+		if !ok {
+			return nil, errors.New("dropout layer is missing \"rate\"")
+		}
+		dropout, err := NewDropoutLayer(rate, nil)
+		if err != nil {
+			return nil, err
+		}
+		layer = dropout
+	default:
+		return nil, fmt.Errorf("unknown layer type %q", spec.Type)
+	}
+
+	if err := CopyParams(layer.Params(), spec.Params); err != nil {
+		return nil, fmt.Errorf("%s parameters: %w", spec.Type, err)
+	}
+	return layer, nil
+}
+
+// specInt reads a positive integer configuration value.
+func specInt(spec layerSpec, key string) (int, error) {
+	v, ok := spec.Config[key]
+	if !ok {
+		return 0, fmt.Errorf("%s layer is missing %q", spec.Type, key)
+	}
+	if v != math.Trunc(v) || v <= 0 || v > math.MaxInt32 {
+		return 0, fmt.Errorf("%s layer has invalid %q: %v", spec.Type, key, v)
+	}
+	return int(v), nil
+}
+
+//? ------------------------------
+//? JSON Encoding
+//? ------------------------------
+
+// decodeJSONModel parses and verifies a JSON model document.
+func decodeJSONModel(r io.Reader) ([]layerSpec, error) {
+	var doc modelJSON
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding model JSON: %w", err)
+	}
+	if doc.Format != modelFormatName {
+		return nil, fmt.Errorf("unrecognized model format %q", doc.Format)
+	}
+	if doc.Version < 1 || doc.Version > modelVersion {
+		return nil, fmt.Errorf("unsupported model version %d", doc.Version)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, doc.Layers); err != nil {
+		return nil, fmt.Errorf("decoding layers: %w", err)
+	}
+	if sum := fmt.Sprintf("%08x", crc32.ChecksumIEEE(compact.Bytes())); sum != doc.Checksum {
+		return nil, fmt.Errorf("checksum mismatch: file says %s, layers hash to %s", doc.Checksum, sum)
+	}
+
+	var specs []layerSpec
+	if err := json.Unmarshal(doc.Layers, &specs); err != nil {
+		return nil, fmt.Errorf("decoding layers: %w", err)
+	}
+	return specs, nil
+}
+
+//? ------------------------------
+//? Binary Encoding
+//? ------------------------------
+
+// decodeBinaryModel reads the header, verifies the checksum and decodes the payload.
+func decodeBinaryModel(r io.Reader) ([]layerSpec, error) {
+	var header struct {
+		Magic   [4]byte
+		Version uint16
+		Length  uint32
+	}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("reading model header: %w", err)
+	}
+	if string(header.Magic[:]) != modelMagic {
+		return nil, errors.New("not an nn-go model file")
+	}
+	if header.Version < 1 || header.Version > modelVersion {
+		return nil, fmt.Errorf("unsupported model version %d", header.Version)
+	}
+	if header.Length > maxModelPayload {
+		return nil, fmt.Errorf("model payload of %d bytes exceeds format limit", header.Length)
+	}
+
+	payload := make([]byte, header.Length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, fmt.Errorf("reading model payload: %w", err)
+	}
+	var checksum uint32
+	if err := binary.Read(r, binary.LittleEndian, &checksum); err != nil {
+		return nil, fmt.Errorf("reading model checksum: %w", err)
+	}
+	if sum := crc32.ChecksumIEEE(payload); sum != checksum {
+		return nil, fmt.Errorf("checksum mismatch: file says %08x, payload hashes to %08x", checksum, sum)
+	}
+
+	return decodeLayerSpecs(bytes.NewReader(payload))
+}
+
+// encodeLayerSpecs writes the binary payload:
+//
+//	count u32, then per layer: type str, activation str,
+//	config count u32 + (key str, value f64)…, param count u32 + (len u32, f64…)…
+//
+// Strings are a u16 length followed by UTF-8 bytes.
+func encodeLayerSpecs(w *bytes.Buffer, specs []layerSpec) error {
+	le := binary.LittleEndian
+	binary.Write(w, le, uint32(len(specs)))
+	for _, spec := range specs {
+		if err := writeString(w, spec.Type); err != nil {
+			return err
+		}
+		if err := writeString(w, string(spec.Activation)); err != nil {
+			return err
+		}
+
+		keys := make([]string, 0, len(spec.Config))
+		for k := range spec.Config {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		binary.Write(w, le, uint32(len(keys)))
+		for _, k := range keys {
+			if err := writeString(w, k); err != nil {
+				return err
+			}
+			binary.Write(w, le, spec.Config[k])
+		}
+
+		binary.Write(w, le, uint32(len(spec.Params)))
+		for _, p := range spec.Params {
+			binary.Write(w, le, uint32(len(p)))
+			binary.Write(w, le, p)
+		}
+	}
+	return nil
+}
+
+// decodeLayerSpecs is the inverse of encodeLayerSpecs.
+func decodeLayerSpecs(r *bytes.Reader) ([]layerSpec, error) {
+	le := binary.LittleEndian
+	var count uint32
+	if err := binary.Read(r, le, &count); err != nil {
+		return nil, fmt.Errorf("reading layer count: %w", err)
+	}
+
+	var specs []layerSpec
+	for i := uint32(0); i < count; i++ {
+		var spec layerSpec
+		var err error
+		if spec.Type, err = readString(r); err != nil {
+			return nil, fmt.Errorf("layer %d type: %w", i, err)
+		}
+		activation, err := readString(r)
+		if err != nil {
+			return nil, fmt.Errorf("layer %d activation: %w", i, err)
+		}
+		spec.Activation = ActivationType(activation)
+
+		var nConfig uint32
+		if err := binary.Read(r, le, &nConfig); err != nil {
+			return nil, fmt.Errorf("layer %d config: %w", i, err)
+		}
+		if nConfig > 0 {
+			spec.Config = make(map[string]float64, nConfig)
+		}
+		for j := uint32(0); j < nConfig; j++ {
+			key, err := readString(r)
+			if err != nil {
+				return nil, fmt.Errorf("layer %d config: %w", i, err)
+			}
+			var value float64
+			if err := binary.Read(r, le, &value); err != nil {
+				return nil, fmt.Errorf("layer %d config %q: %w", i, key, err)
+			}
+			spec.Config[key] = value
+		}
+
+		var nParams uint32
+		if err := binary.Read(r, le, &nParams); err != nil {
+			return nil, fmt.Errorf("layer %d params: %w", i, err)
+		}
+		for j := uint32(0); j < nParams; j++ {
+			var n uint32
+			if err := binary.Read(r, le, &n); err != nil {
+				return nil, fmt.Errorf("layer %d param %d: %w", i, j, err)
+			}
+			if int64(n)*8 > int64(r.Len()) {
+				return nil, fmt.Errorf("layer %d param %d: length %d exceeds payload", i, j, n)
+			}
+			p := make([]float64, n)
+			if err := binary.Read(r, le, p); err != nil {
+				return nil, fmt.Errorf("layer %d param %d: %w", i, j, err)
+			}
+			spec.Params = append(spec.Params, p)
+		}
+		specs = append(specs, spec)
+	}
+
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after last layer", r.Len())
+	}
+	return specs, nil
+}
+
+// writeString writes a u16 length-prefixed string.
+func writeString(w *bytes.Buffer, s string) error {
+	if len(s) > math.MaxUint16 {
+		return fmt.Errorf("string of %d bytes is too long to encode", len(s))
+	}
+	binary.Write(w, binary.LittleEndian, uint16(len(s)))
+	w.WriteString(s)
+	return nil
+}
+
+// readString reads a u16 length-prefixed string.
+func readString(r *bytes.Reader) (string, error) {
+	var n uint16
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return "", err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,184 @@
+package nn
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/SobhanYasami/nn-go/internal/rng"
+)
+
+// serializableModel builds a model with every serializable layer type and
+// random values in all of its parameters, so none of them match the defaults
+// Load starts from.
+func serializableModel(t *testing.T) *Sequential {
+	t.Helper()
+	dense1, err := NewDenseLayer(3, 4)
+	if err != nil {
+		t.Fatalf("NewDenseLayer() returned error: %v", err)
+	}
+	leaky, err := NewActivationLayerWithAlpha(LeakyReLU, 0.2)
+	if err != nil {
+		t.Fatalf("NewActivationLayerWithAlpha() returned error: %v", err)
+	}
+	prelu, err := NewPReLULayer(4, 0.1)
+	if err != nil {
+		t.Fatalf("NewPReLULayer() returned error: %v", err)
+	}
+	swish, err := NewSwishLayer(4, 1)
+	if err != nil {
+		t.Fatalf("NewSwishLayer() returned error: %v", err)
+	}
+	dropout, err := NewDropoutLayer(0.3, nil)
+	if err != nil {
+		t.Fatalf("NewDropoutLayer() returned error: %v", err)
+	}
+	dense2, err := NewDenseLayer(4, 2)
+	if err != nil {
+		t.Fatalf("NewDenseLayer() returned error: %v", err)
+	}
+	softmax := NewActivationLayer(Softmax)
+	softmax.Temperature = 0.5
+
+	model := NewSequential(dense1, leaky, prelu, swish, dropout, dense2, softmax)
+	r := rng.New(3)
+	for _, p := range model.Params() {
+		for j := range p {
+			p[j] = r.NormFloat64()
+		}
+	}
+	return model
+}
+
+// predictions runs the model in inference mode on a fixed batch.
+func predictions(t *testing.T, model *Sequential) [][]float64 {
+	t.Helper()
+	model.SetTraining(false)
+	out, err := model.Forward(tensor(t, [][]float64{{0.5, -1.2, 2.0}, {-0.3, 0.8, 0.1}}))
+	if err != nil {
+		t.Fatalf("Forward() returned error: %v", err)
+	}
+	return out.ToMatrix()
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	model := serializableModel(t)
+	want := predictions(t, model)
+
+	for _, format := range []struct {
+		name string
+		save func(*bytes.Buffer) error
+	}{
+		{"binary", func(b *bytes.Buffer) error { return model.Save(b) }},
+		{"json", func(b *bytes.Buffer) error { return model.SaveJSON(b) }},
+	} {
+		var buf bytes.Buffer
+		if err := format.save(&buf); err != nil {
+			t.Fatalf("%s: save returned error: %v", format.name, err)
+		}
+		loaded, err := Load(&buf)
+		if err != nil {
+			t.Fatalf("%s: Load() returned error: %v", format.name, err)
+		}
+		if len(loaded.Layers) != len(model.Layers) {
+			t.Fatalf("%s: loaded %d layers; want %d", format.name, len(loaded.Layers), len(model.Layers))
+		}
+		got := predictions(t, loaded)
+		for i := range want {
+			for j := range want[i] {
+				if got[i][j] != want[i][j] {
+					t.Errorf("%s: output[%d][%d] = %v after Load; want %v", format.name, i, j, got[i][j], want[i][j])
+				}
+			}
+		}
+	}
+}
+
+func TestActivationAlphaRoundTrip(t *testing.T) {
+	zero, err := NewActivationLayerWithAlpha(LeakyReLU, 0)
+	if err != nil {
+		t.Fatalf("NewActivationLayerWithAlpha() returned error: %v", err)
+	}
+	elu, err := NewActivationLayerWithAlpha(ELU, 0.5)
+	if err != nil {
+		t.Fatalf("NewActivationLayerWithAlpha() returned error: %v", err)
+	}
+	// ReLU ignores Alpha, so a stray value is not saved rather than rejected on Load.
+	relu := NewActivationLayer(ReLU)
+	relu.Alpha = 0.3
+	model := NewSequential(zero, elu, relu)
+
+	var buf bytes.Buffer
+	if err := model.SaveJSON(&buf); err != nil {
+		t.Fatalf("SaveJSON() returned error: %v", err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	for i, want := range []float64{0, 0.5, 0} {
+		if got := loaded.Layers[i].(*ActivationLayer).Alpha; got != want {
+			t.Errorf("layer %d Alpha = %v after Load; want %v", i, got, want)
+		}
+	}
+
+	invalid := NewActivationLayer(LeakyReLU)
+	invalid.Alpha = -1
+	if err := NewSequential(invalid).SaveJSON(&buf); err == nil {
+		t.Error("SaveJSON() expected error for a negative alpha, got nil")
+	}
+}
+
+func TestLoadRejectsCorruptPayload(t *testing.T) {
+	model := serializableModel(t)
+
+	var bin bytes.Buffer
+	if err := model.Save(&bin); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+	data := bin.Bytes()
+	// Flip one bit in the middle of the payload, well past the 10-byte header.
+	data[len(data)/2] ^= 0x01
+	if _, err := Load(bytes.NewReader(data)); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("Load(corrupt binary) error = %v; want checksum mismatch", err)
+	}
+
+	var js bytes.Buffer
+	if err := model.SaveJSON(&js); err != nil {
+		t.Fatalf("SaveJSON() returned error: %v", err)
+	}
+	corrupt := strings.Replace(js.String(), `"rate": 0.3`, `"rate": 0.4`, 1)
+	if corrupt == js.String() {
+		t.Fatal("test setup: dropout rate not found in JSON")
+	}
+	if _, err := Load(strings.NewReader(corrupt)); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("Load(corrupt JSON) error = %v; want checksum mismatch", err)
+	}
+}
+
+func TestLoadRejectsUnknownVersion(t *testing.T) {
+	model := serializableModel(t)
+
+	var bin bytes.Buffer
+	if err := model.Save(&bin); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+	data := bin.Bytes()
+	// The version is the uint16 after the magic.
+	data[len(modelMagic)] = modelVersion + 1
+	if _, err := Load(bytes.NewReader(data)); err == nil || !strings.Contains(err.Error(), "unsupported model version") {
+		t.Errorf("Load(binary version %d) error = %v; want unsupported version", modelVersion+1, err)
+	}
+
+	var js bytes.Buffer
+	if err := model.SaveJSON(&js); err != nil {
+		t.Fatalf("SaveJSON() returned error: %v", err)
+	}
+	future := strings.Replace(js.String(), `"version": 1,`, `"version": 2,`, 1)
+	if future == js.String() {
+		t.Fatal("test setup: version not found in JSON")
+	}
+	if _, err := Load(strings.NewReader(future)); err == nil || !strings.Contains(err.Error(), "unsupported model version") {
+		t.Errorf("Load(JSON version 2) error = %v; want unsupported version", err)
+	}
+}
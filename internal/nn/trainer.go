@@ -0,0 +1,405 @@
+package nn
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/SobhanYasami/nn-go/internal/mathx"
+	"github.com/SobhanYasami/nn-go/internal/rng"
+	"github.com/SobhanYasami/nn-go/internal/scheduler"
+	"github.com/SobhanYasami/nn-go/pkg/logger"
+)
+
+// Dataset is a set of samples with their targets. The first axis of X (and
+// of Targets) indexes the samples. Classification data sets Y to the class
+// labels; regression and multi-label data set Targets.
+type Dataset struct {
+	X       *mathx.Tensor
+	Y       []int
+	Targets *mathx.Tensor
+}
+
+// Len returns the number of samples.
+func (d Dataset) Len() int {
+	if d.X == nil || d.X.Dims() == 0 {
+		return 0
+	}
+	return d.X.Dim(0)
+}
+
+// Validate checks that the dataset is non-empty and features and targets line up.
+func (d Dataset) Validate() error {
+	if d.Len() == 0 || d.X.Size() == 0 {
+		return errors.New("dataset cannot be empty")
+	}
+	if d.Y == nil && d.Targets == nil {
+		return errors.New("dataset has neither labels nor targets")
+	}
+	if d.Y != nil && d.Len() != len(d.Y) {
+		return fmt.Errorf("dataset has %d samples but %d labels", d.Len(), len(d.Y))
+	}
+	if d.Targets != nil && (d.Targets.Dims() == 0 || d.Len() != d.Targets.Dim(0)) {
+		return fmt.Errorf("dataset has %d samples but targets of shape %v", d.Len(), d.Targets.Shape())
+	}
+	return nil
+}
+
+// Target returns the dataset's labels and targets as a loss Target.
+func (d Dataset) Target() Target {
+	return Target{Labels: d.Y, Values: d.Targets}
+}
+
+// batch gathers the samples at the given indices into contiguous tensors.
+func (d Dataset) batch(indices []int) (Dataset, error) {
+	var b Dataset
+	var err error
+	if b.X, err = d.X.Take(indices); err != nil {
+		return Dataset{}, err
+	}
+	if d.Targets != nil {
+		if b.Targets, err = d.Targets.Take(indices); err != nil {
+			return Dataset{}, err
+		}
+	}
+	if d.Y != nil {
+		b.Y = make([]int, len(indices))
+		for i, idx := range indices {
+			b.Y[i] = d.Y[idx]
+		}
+	}
+	return b, nil
+}
+
+//? ------------------------------
+//? Callbacks
+//? ------------------------------
+
+// Logs holds the metrics reported to callbacks, keyed by name
+// ("loss", "accuracy", "val_loss", "val_accuracy", "lr").
+type Logs map[string]float64
+
+// Callback hooks into the training loop. Returning an error aborts training;
+// call Trainer.Stop to end training gracefully after the current step.
+type Callback interface {
+	// OnBatchEnd runs after every optimizer step with the batch metrics.
+	OnBatchEnd(t *Trainer, batch int, logs Logs) error
+	// OnEpochEnd runs after every epoch with the epoch (and validation) metrics.
+	OnEpochEnd(t *Trainer, epoch int, logs Logs) error
+	// OnTrainEnd runs once when Fit finishes, with the logs of the last epoch.
+	OnTrainEnd(t *Trainer, logs Logs) error
+}
+
+// StatefulCallback is implemented by callbacks that carry progress across
+// epochs, such as EarlyStopping. Checkpoints save that state so a resumed run
+// continues exactly like an uninterrupted one.
+type StatefulCallback interface {
+	Callback
+	// State encodes the callback's progress.
+	State() ([]byte, error)
+	// SetState restores progress encoded by State.
+	SetState(state []byte) error
+}
+
+// CallbackFuncs adapts plain functions to the Callback interface.
+// Any field may be nil.
+type CallbackFuncs struct {
+	BatchEnd func(t *Trainer, batch int, logs Logs) error
+	EpochEnd func(t *Trainer, epoch int, logs Logs) error
+	TrainEnd func(t *Trainer, logs Logs) error
+}
+
+// OnBatchEnd calls BatchEnd if set.
+func (c CallbackFuncs) OnBatchEnd(t *Trainer, batch int, logs Logs) error {
+	if c.BatchEnd == nil {
+		return nil
+	}
+	return c.BatchEnd(t, batch, logs)
+}
+
+// OnEpochEnd calls EpochEnd if set.
+func (c CallbackFuncs) OnEpochEnd(t *Trainer, epoch int, logs Logs) error {
+	if c.EpochEnd == nil {
+		return nil
+	}
+	return c.EpochEnd(t, epoch, logs)
+}
+
+// OnTrainEnd calls TrainEnd if set.
+func (c CallbackFuncs) OnTrainEnd(t *Trainer, logs Logs) error {
+	if c.TrainEnd == nil {
+		return nil
+	}
+	return c.TrainEnd(t, logs)
+}
+
+// NewLoggingCallback logs the epoch metrics every `every` epochs.
+func NewLoggingCallback(log *logger.Logger, every int) Callback {
+	if every <= 0 {
+		every = 1
+	}
+	return CallbackFuncs{
+		EpochEnd: func(t *Trainer, epoch int, logs Logs) error {
+			if epoch%every != 0 {
+				return nil
+			}
+			msg := fmt.Sprintf("Epoch %4d | loss: %.6f", epoch, logs["loss"])
+			if acc, ok := logs["accuracy"]; ok {
+				msg += fmt.Sprintf(" | acc: %.4f", acc)
+			}
+			if valLoss, ok := logs["val_loss"]; ok {
+				msg += fmt.Sprintf(" | val_loss: %.6f", valLoss)
+			}
+			if valAcc, ok := logs["val_accuracy"]; ok {
+				msg += fmt.Sprintf(" | val_acc: %.4f", valAcc)
+			}
+			log.Info("%s | lr: %.6f", msg, logs["lr"])
+			return nil
+		},
+	}
+}
+
+//? ------------------------------
+//? Trainer
+//? ------------------------------
+
+// Trainer runs mini-batch gradient descent on a Sequential model.
+type Trainer struct {
+	Model     *Sequential
+	Loss      Loss
+	Optimizer Optimizer
+
+	Train      Dataset
+	Validation *Dataset // optional; adds val_loss / val_accuracy to the epoch logs
+
+	// Scheduler, if set, is applied before every epoch and stepped after it
+	// with val_loss (or loss when there is no validation data).
+	Scheduler scheduler.Scheduler
+
+	BatchSize int  // samples per step; 0 or more than the dataset means full batch
+	Shuffle   bool // reshuffle the training set at the start of every epoch
+	Seed      uint64
+	// RNG drives shuffling. When nil, Fit creates it from Seed; set it to a
+	// stream of a shared root to seed a whole run at once.
+	RNG       *rng.RNG
+	Callbacks []Callback
+
+	epoch   int
+	step    int
+	stopped bool
+}
+
+// NewTrainer creates a trainer with batch size 32, shuffling enabled and seed 0.
+func NewTrainer(model *Sequential, loss Loss, optimizer Optimizer, train Dataset) (*Trainer, error) {
+	if model == nil || loss == nil || optimizer == nil {
+		return nil, errors.New("model, loss and optimizer are required")
+	}
+	if err := train.Validate(); err != nil {
+		return nil, fmt.Errorf("training data: %w", err)
+	}
+	return &Trainer{
+		Model:     model,
+		Loss:      loss,
+		Optimizer: optimizer,
+		Train:     train,
+		BatchSize: 32,
+		Shuffle:   true,
+	}, nil
+}
+
+// AddCallback registers callbacks, invoked in registration order.
+func (t *Trainer) AddCallback(callbacks ...Callback) {
+	t.Callbacks = append(t.Callbacks, callbacks...)
+}
+
+// Stop asks the trainer to finish after the current batch.
+func (t *Trainer) Stop() { t.stopped = true }
+
+// Epoch returns the number of completed epochs.
+func (t *Trainer) Epoch() int { return t.epoch }
+
+// Step returns the number of optimizer steps taken so far.
+func (t *Trainer) Step() int { return t.step }
+
+// Fit trains until `epochs` epochs have been completed in total and returns
+// the logs of the epochs run by this call.
+func (t *Trainer) Fit(epochs int) ([]Logs, error) {
+	if err := t.Train.Validate(); err != nil {
+		return nil, fmt.Errorf("training data: %w", err)
+	}
+	if t.Validation != nil {
+		if err := t.Validation.Validate(); err != nil {
+			return nil, fmt.Errorf("validation data: %w", err)
+		}
+	}
+	if t.RNG == nil {
+		t.RNG = rng.New(t.Seed)
+	}
+	// Evaluate switches the model to inference mode; leave it there when done.
+	defer t.Model.SetTraining(false)
+
+	t.stopped = false
+	var history []Logs
+	for t.epoch < epochs && !t.stopped {
+		logs, err := t.runEpoch()
+		if err != nil {
+			return history, fmt.Errorf("epoch %d: %w", t.epoch, err)
+		}
+		history = append(history, logs)
+
+		// Advance all counters before the callbacks run so that a checkpoint
+		// taken in OnEpochEnd captures a consistent end-of-epoch state.
+		if t.Scheduler != nil {
+			monitor := logs["loss"]
+			if valLoss, ok := logs["val_loss"]; ok {
+				monitor = valLoss
+			}
+			t.Scheduler.Step(monitor)
+		}
+		epoch := t.epoch
+		t.epoch++
+
+		for _, cb := range t.Callbacks {
+			if err := cb.OnEpochEnd(t, epoch, logs); err != nil {
+				return history, fmt.Errorf("epoch %d callback: %w", epoch, err)
+			}
+		}
+	}
+
+	var last Logs
+	if len(history) > 0 {
+		last = history[len(history)-1]
+	}
+	for _, cb := range t.Callbacks {
+		if err := cb.OnTrainEnd(t, last); err != nil {
+			return history, fmt.Errorf("train end callback: %w", err)
+		}
+	}
+	return history, nil
+}
+
+// runEpoch performs one pass over the training data.
+func (t *Trainer) runEpoch() (Logs, error) {
+	if t.Scheduler != nil {
+		scheduler.Apply(t.Scheduler, t.Optimizer)
+	}
+
+	n := t.Train.Len()
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	if t.Shuffle {
+		t.RNG.Shuffle(n, func(i, j int) { order[i], order[j] = order[j], order[i] })
+	}
+
+	t.Model.SetTraining(true)
+	batchSize := t.BatchSize
+	if batchSize <= 0 || batchSize > n {
+		batchSize = n
+	}
+
+	var sumLoss float64
+	var correct, seen int
+	for batch, start := 0, 0; start < n && !t.stopped; batch, start = batch+1, start+batchSize {
+		end := min(start+batchSize, n)
+		data, err := t.Train.batch(order[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		outputs, err := t.Model.Forward(data.X)
+		if err != nil {
+			return nil, err
+		}
+		loss, err := t.Loss.Forward(outputs, data.Target())
+		if err != nil {
+			return nil, err
+		}
+		dOutputs, err := t.Loss.Backward(outputs, data.Target())
+		if err != nil {
+			return nil, err
+		}
+		if _, err := t.Model.Backward(dOutputs); err != nil {
+			return nil, err
+		}
+		if err := t.Optimizer.Step(t.Model.Params(), t.Model.Grads()); err != nil {
+			return nil, err
+		}
+		t.step++
+
+		batchCorrect := countCorrect(outputs, data.Y)
+		sumLoss += loss * float64(data.Len())
+		correct += batchCorrect
+		seen += data.Len()
+
+		batchLogs := Logs{
+			"loss": loss,
+			"lr":   t.Optimizer.LearningRate(),
+		}
+		if data.Y != nil {
+			batchLogs["accuracy"] = float64(batchCorrect) / float64(data.Len())
+		}
+		for _, cb := range t.Callbacks {
+			if err := cb.OnBatchEnd(t, batch, batchLogs); err != nil {
+				return nil, fmt.Errorf("batch %d callback: %w", batch, err)
+			}
+		}
+	}
+
+	logs := Logs{
+		"loss": sumLoss / float64(seen),
+		"lr":   t.Optimizer.LearningRate(),
+	}
+	if t.Train.Y != nil {
+		logs["accuracy"] = float64(correct) / float64(seen)
+	}
+	if t.Validation != nil {
+		valLogs, err := t.Evaluate(*t.Validation)
+		if err != nil {
+			return nil, fmt.Errorf("validation: %w", err)
+		}
+		logs["val_loss"] = valLogs["loss"]
+		if acc, ok := valLogs["accuracy"]; ok {
+			logs["val_accuracy"] = acc
+		}
+	}
+	return logs, nil
+}
+
+// Evaluate computes the loss of the model on data without training, plus the
+// accuracy when data has class labels. It puts the model in inference mode.
+func (t *Trainer) Evaluate(data Dataset) (Logs, error) {
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+	t.Model.SetTraining(false)
+	outputs, err := t.Model.Forward(data.X)
+	if err != nil {
+		return nil, err
+	}
+	loss, err := t.Loss.Forward(outputs, data.Target())
+	if err != nil {
+		return nil, err
+	}
+	logs := Logs{"loss": loss}
+	if data.Y != nil {
+		logs["accuracy"] = float64(countCorrect(outputs, data.Y)) / float64(data.Len())
+	}
+	return logs, nil
+}
+
+// countCorrect returns how many rows of outputs have their arg-max at the true
+// label. It returns 0 when there are no labels.
+func countCorrect(outputs *mathx.Tensor, yTrue []int) int {
+	if yTrue == nil {
+		return 0
+	}
+	rows := outputs.Rows()
+	var correct int
+	for i, label := range yTrue {
+		if argMax(rows[i]) == label {
+			correct++
+		}
+	}
+	return correct
+}
@@ -0,0 +1,328 @@
+package nn
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	dataset "github.com/SobhanYasami/nn-go/internal/data"
+	"github.com/SobhanYasami/nn-go/internal/mathx"
+	"github.com/SobhanYasami/nn-go/internal/rng"
+)
+
+// newTestTrainer builds a trainer for a small model whose data, weights,
+// dropout masks and shuffling all come from streams of a single seed.
+func newTestTrainer(t *testing.T, seed uint64) *Trainer {
+	t.Helper()
+	root := rng.New(seed)
+
+	X, y := dataset.CreateDataWithRNG(40, 3, root.Stream("train"))
+	model := NewSequential()
+	for i, size := range [][2]int{{2, 16}, {16, 3}} {
+		layer, err := NewDenseLayer(size[0], size[1],
+			WithWeightInit(HeNormal()), WithRand(root.Stream(fmt.Sprintf("dense%d", i)).Rand))
+		if err != nil {
+			t.Fatalf("NewDenseLayer() returned error: %v", err)
+		}
+		model.Add(layer)
+		if i == 0 {
+			dropout, err := NewDropoutLayer(0.2, root.Stream("dropout"))
+			if err != nil {
+				t.Fatalf("NewDropoutLayer() returned error: %v", err)
+			}
+			model.Add(NewActivationLayer(ReLU), dropout)
+		}
+	}
+	model.Add(NewActivationLayer(Softmax))
+
+	optimizer, err := NewAdam(0.01, 0.9, 0.999, 1e-8)
+	if err != nil {
+		t.Fatalf("NewAdam() returned error: %v", err)
+	}
+	trainer, err := NewTrainer(model, CategoricalCrossEntropy{}, optimizer, Dataset{X: tensor(t, X), Y: y})
+	if err != nil {
+		t.Fatalf("NewTrainer() returned error: %v", err)
+	}
+	trainer.BatchSize = 16
+	trainer.RNG = root.Stream("shuffle")
+	return trainer
+}
+
+// trainRun trains a newTestTrainer model for 5 epochs and returns its
+// parameters and history.
+func trainRun(t *testing.T, seed uint64) ([][]float64, []Logs) {
+	t.Helper()
+	trainer := newTestTrainer(t, seed)
+	history, err := trainer.Fit(5)
+	if err != nil {
+		t.Fatalf("Fit() returned error: %v", err)
+	}
+	return CloneParams(trainer.Model.Params()), history
+}
+
+func TestFitIsDeterministic(t *testing.T) {
+	params1, history1 := trainRun(t, 7)
+	params2, history2 := trainRun(t, 7)
+
+	for i := range params1 {
+		for j := range params1[i] {
+			if params1[i][j] != params2[i][j] {
+				t.Fatalf("param[%d][%d] differs between runs: %v vs %v", i, j, params1[i][j], params2[i][j])
+			}
+		}
+	}
+	for epoch := range history1 {
+		if history1[epoch]["loss"] != history2[epoch]["loss"] {
+			t.Errorf("epoch %d loss differs between runs: %v vs %v",
+				epoch, history1[epoch]["loss"], history2[epoch]["loss"])
+		}
+	}
+
+	params3, _ := trainRun(t, 8)
+	if params3[0][0] == params1[0][0] {
+		t.Errorf("different seeds produced the same first weight %v", params1[0][0])
+	}
+}
+
+// spyLayer is an identity layer that records, for every training batch, the
+// first feature of each sample. Datasets built by indexedDataset store the
+// sample index there, so the records show which samples each batch held.
+type spyLayer struct {
+	batches [][]int
+}
+
+func (s *spyLayer) Forward(inputs *mathx.Tensor) (*mathx.Tensor, error) {
+	ids := make([]int, inputs.Dim(0))
+	for i := range ids {
+		ids[i] = int(inputs.At(i, 0))
+	}
+	s.batches = append(s.batches, ids)
+	return inputs, nil
+}
+
+func (s *spyLayer) Backward(dOutputs *mathx.Tensor) (*mathx.Tensor, error) { return dOutputs, nil }
+func (s *spyLayer) Params() [][]float64                                    { return nil }
+func (s *spyLayer) Grads() [][]float64                                     { return nil }
+
+// indexedDataset has n samples whose first feature is the sample index and
+// whose label is its parity.
+func indexedDataset(t *testing.T, n int) Dataset {
+	t.Helper()
+	X := make([][]float64, n)
+	y := make([]int, n)
+	for i := range X {
+		X[i] = []float64{float64(i), float64(i%3) - 1}
+		y[i] = i % 2
+	}
+	return Dataset{X: tensor(t, X), Y: y}
+}
+
+// spyTrainer trains spy → Dense(2→2) → Softmax with SGD on indexedDataset(n).
+func spyTrainer(t *testing.T, n, batchSize int) (*Trainer, *spyLayer) {
+	t.Helper()
+	spy := &spyLayer{}
+	dense, err := NewDenseLayer(2, 2, WithWeightInit(Constant(0.1)))
+	if err != nil {
+		t.Fatalf("NewDenseLayer() returned error: %v", err)
+	}
+	optimizer, err := NewSGD(0.01, 0, false)
+	if err != nil {
+		t.Fatalf("NewSGD() returned error: %v", err)
+	}
+	model := NewSequential(spy, dense, NewActivationLayer(Softmax))
+	trainer, err := NewTrainer(model, CategoricalCrossEntropy{}, optimizer, indexedDataset(t, n))
+	if err != nil {
+		t.Fatalf("NewTrainer() returned error: %v", err)
+	}
+	trainer.BatchSize = batchSize
+	return trainer, spy
+}
+
+func TestFitBatches(t *testing.T) {
+	trainer, spy := spyTrainer(t, 10, 4)
+	trainer.Shuffle = false
+	var batchLosses []float64
+	trainer.AddCallback(CallbackFuncs{BatchEnd: func(_ *Trainer, _ int, logs Logs) error {
+		batchLosses = append(batchLosses, logs["loss"])
+		return nil
+	}})
+
+	history, err := trainer.Fit(1)
+	if err != nil {
+		t.Fatalf("Fit() returned error: %v", err)
+	}
+	want := [][]int{{0, 1, 2, 3}, {4, 5, 6, 7}, {8, 9}}
+	if fmt.Sprint(spy.batches) != fmt.Sprint(want) {
+		t.Errorf("batches = %v; want %v", spy.batches, want)
+	}
+	if trainer.Step() != 3 || trainer.Epoch() != 1 {
+		t.Errorf("Step(), Epoch() = %d, %d; want 3, 1", trainer.Step(), trainer.Epoch())
+	}
+	// The epoch loss weights each batch by its size.
+	wantLoss := (4*batchLosses[0] + 4*batchLosses[1] + 2*batchLosses[2]) / 10
+	if !almostEqual(history[0]["loss"], wantLoss, 1e-12) {
+		t.Errorf("epoch loss = %v; want %v", history[0]["loss"], wantLoss)
+	}
+
+	// A batch size of 0 or above the dataset size trains on the full batch.
+	for _, batchSize := range []int{0, 25} {
+		trainer, spy := spyTrainer(t, 10, batchSize)
+		if _, err := trainer.Fit(1); err != nil {
+			t.Fatalf("Fit() returned error: %v", err)
+		}
+		if len(spy.batches) != 1 || len(spy.batches[0]) != 10 {
+			t.Errorf("batch size %d: batches = %v; want one batch of 10", batchSize, spy.batches)
+		}
+	}
+}
+
+func TestFitShufflesEveryEpoch(t *testing.T) {
+	epochOrders := func(seed uint64) []string {
+		trainer, spy := spyTrainer(t, 12, 5)
+		trainer.Seed = seed
+		if _, err := trainer.Fit(3); err != nil {
+			t.Fatalf("Fit() returned error: %v", err)
+		}
+		var orders []string
+		for epoch := 0; epoch < 3; epoch++ {
+			var order []int
+			for _, batch := range spy.batches[3*epoch : 3*epoch+3] {
+				order = append(order, batch...)
+			}
+			seen := make(map[int]bool)
+			for _, id := range order {
+				seen[id] = true
+			}
+			if len(order) != 12 || len(seen) != 12 {
+				t.Errorf("seed %d epoch %d visited %v; want every sample once", seed, epoch, order)
+			}
+			orders = append(orders, fmt.Sprint(order))
+		}
+		return orders
+	}
+
+	orders := epochOrders(5)
+	if orders[0] == orders[1] || orders[1] == orders[2] {
+		t.Errorf("epoch orders %v; want a new shuffle every epoch", orders)
+	}
+	if again := epochOrders(5); fmt.Sprint(again) != fmt.Sprint(orders) {
+		t.Errorf("seed 5 orders %v on the second run; want %v", again, orders)
+	}
+	if other := epochOrders(6); fmt.Sprint(other) == fmt.Sprint(orders) {
+		t.Errorf("seeds 5 and 6 produced the same orders %v", orders)
+	}
+}
+
+func TestFitValidationLogs(t *testing.T) {
+	trainer, _ := spyTrainer(t, 10, 4)
+	validation := indexedDataset(t, 6)
+	trainer.Validation = &validation
+
+	history, err := trainer.Fit(2)
+	if err != nil {
+		t.Fatalf("Fit() returned error: %v", err)
+	}
+	for epoch, logs := range history {
+		for _, key := range []string{"loss", "accuracy", "lr", "val_loss", "val_accuracy"} {
+			if _, ok := logs[key]; !ok {
+				t.Errorf("epoch %d logs %v; missing %q", epoch, logs, key)
+			}
+		}
+	}
+	// The model is unchanged after the last epoch, so evaluating it again
+	// reproduces that epoch's validation metrics.
+	want, err := trainer.Evaluate(validation)
+	if err != nil {
+		t.Fatalf("Evaluate() returned error: %v", err)
+	}
+	last := history[len(history)-1]
+	if last["val_loss"] != want["loss"] || last["val_accuracy"] != want["accuracy"] {
+		t.Errorf("last epoch val_loss, val_accuracy = %v, %v; want %v, %v",
+			last["val_loss"], last["val_accuracy"], want["loss"], want["accuracy"])
+	}
+}
+
+func TestFitCallbackOrder(t *testing.T) {
+	trainer, _ := spyTrainer(t, 5, 2)
+	var events []string
+	for _, name := range []string{"a", "b"} {
+		trainer.AddCallback(CallbackFuncs{
+			BatchEnd: func(tr *Trainer, batch int, _ Logs) error {
+				events = append(events, fmt.Sprintf("%s:batch%d@%d", name, batch, tr.Step()))
+				return nil
+			},
+			EpochEnd: func(tr *Trainer, epoch int, _ Logs) error {
+				events = append(events, fmt.Sprintf("%s:epoch%d@%d", name, epoch, tr.Epoch()))
+				return nil
+			},
+			TrainEnd: func(*Trainer, Logs) error {
+				events = append(events, name+":end")
+				return nil
+			},
+		})
+	}
+
+	if _, err := trainer.Fit(2); err != nil {
+		t.Fatalf("Fit() returned error: %v", err)
+	}
+	// Batch indices restart every epoch; Step counts across epochs and Epoch
+	// has already advanced when OnEpochEnd runs.
+	want := []string{
+		"a:batch0@1", "b:batch0@1", "a:batch1@2", "b:batch1@2", "a:batch2@3", "b:batch2@3",
+		"a:epoch0@1", "b:epoch0@1",
+		"a:batch0@4", "b:batch0@4", "a:batch1@5", "b:batch1@5", "a:batch2@6", "b:batch2@6",
+		"a:epoch1@2", "b:epoch1@2",
+		"a:end", "b:end",
+	}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("callback events\n%v\nwant\n%v", events, want)
+	}
+}
+
+func TestFitStopsOnCallbackError(t *testing.T) {
+	errStop := errors.New("stop here")
+
+	trainer, _ := spyTrainer(t, 10, 4)
+	trainer.AddCallback(CallbackFuncs{EpochEnd: func(_ *Trainer, epoch int, _ Logs) error {
+		if epoch == 1 {
+			return errStop
+		}
+		return nil
+	}})
+	history, err := trainer.Fit(5)
+	if !errors.Is(err, errStop) {
+		t.Errorf("Fit() error = %v; want %v", err, errStop)
+	}
+	if len(history) != 2 || trainer.Epoch() != 2 {
+		t.Errorf("Fit() ran %d epochs (Epoch() = %d) before the error; want 2", len(history), trainer.Epoch())
+	}
+
+	trainer, _ = spyTrainer(t, 10, 4)
+	trainer.AddCallback(CallbackFuncs{BatchEnd: func(tr *Trainer, _ int, _ Logs) error {
+		if tr.Step() == 5 {
+			return errStop
+		}
+		return nil
+	}})
+	if _, err := trainer.Fit(5); !errors.Is(err, errStop) {
+		t.Errorf("Fit() error = %v; want %v", err, errStop)
+	}
+	if trainer.Step() != 5 || trainer.Epoch() != 1 {
+		t.Errorf("Step(), Epoch() = %d, %d after a batch error; want 5, 1", trainer.Step(), trainer.Epoch())
+	}
+
+	// Stop ends training gracefully after the current batch.
+	trainer, _ = spyTrainer(t, 10, 4)
+	trainer.AddCallback(CallbackFuncs{BatchEnd: func(tr *Trainer, _ int, _ Logs) error {
+		if tr.Step() == 2 {
+			tr.Stop()
+		}
+		return nil
+	}})
+	if _, err := trainer.Fit(5); err != nil {
+		t.Errorf("Fit() returned error after Stop: %v", err)
+	}
+	if trainer.Step() != 2 {
+		t.Errorf("Step() = %d after Stop; want 2", trainer.Step())
+	}
+}
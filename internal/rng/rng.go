@@ -0,0 +1,82 @@
+// Package rng provides the seedable random source shared by the library.
+//
+// A run is made reproducible by seeding one root RNG and handing every
+// consumer (weight initialization, dropout, shuffling, dataset generators)
+// its own stream derived from it:
+//
+//	root := rng.New(42)
+//	X, y := dataset.CreateDataWithRNG(300, 3, root.Stream("train"))
+//	layer, _ := nn.NewDenseLayer(2, 8, nn.WithRand(root.Stream("dense0").Rand))
+//	trainer.RNG = root.Stream("shuffle")
+//
+// Named streams depend only on the root seed and the name, so adding or
+// reordering consumers never changes the numbers the others draw.
+package rng
+
+import (
+	"errors"
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+)
+
+// RNG is a PCG-backed random source. It embeds *rand.Rand, so it offers the
+// usual Float64, NormFloat64, IntN, Shuffle, … methods, and it can save and
+// restore its exact position with MarshalBinary / UnmarshalBinary.
+type RNG struct {
+	*rand.Rand
+	src  *rand.PCG
+	seed uint64
+}
+
+// New returns a generator seeded with seed.
+func New(seed uint64) *RNG {
+	src := rand.NewPCG(seed, seed)
+	return &RNG{Rand: rand.New(src), src: src, seed: seed}
+}
+
+// NewFromTime returns a generator seeded from the wall clock, for callers
+// that do not need reproducibility.
+func NewFromTime() *RNG {
+	return New(uint64(time.Now().UnixNano()))
+}
+
+// Seed returns the seed the generator was created with.
+func (r *RNG) Seed() uint64 { return r.seed }
+
+// Stream returns the child generator called name. It depends only on the
+// seed of r and the name, not on how many numbers r has produced, so the
+// same name always yields the same stream.
+func (r *RNG) Stream(name string) *RNG {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return New(mix(r.seed ^ h.Sum64()))
+}
+
+// Split returns a new independent generator seeded from r, advancing r.
+// Successive calls return different streams.
+func (r *RNG) Split() *RNG {
+	return New(mix(r.Uint64()))
+}
+
+// MarshalBinary encodes the current position of the generator.
+func (r *RNG) MarshalBinary() ([]byte, error) {
+	return r.src.MarshalBinary()
+}
+
+// UnmarshalBinary restores a position saved by MarshalBinary.
+func (r *RNG) UnmarshalBinary(data []byte) error {
+	if r.src == nil {
+		return errors.New("rng: UnmarshalBinary on an uninitialized RNG")
+	}
+	return r.src.UnmarshalBinary(data)
+}
+
+// mix is the SplitMix64 finalizer; it spreads nearby seeds (0, 1, 2, …)
+// across the whole state space before they reach PCG.
+func mix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
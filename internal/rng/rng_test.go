@@ -0,0 +1,37 @@
+package rng
+
+import "testing"
+
+func TestStreamDependsOnlyOnSeedAndName(t *testing.T) {
+	a := New(1)
+	b := New(1)
+	b.Uint64() // advancing the parent must not change its streams
+
+	if x, y := a.Stream("init").Uint64(), b.Stream("init").Uint64(); x != y {
+		t.Errorf("Stream(init) = %d and %d; want equal", x, y)
+	}
+	if a.Stream("init").Uint64() == a.Stream("shuffle").Uint64() {
+		t.Error("Stream(init) and Stream(shuffle) produced the same value")
+	}
+	if a.Split().Uint64() == a.Split().Uint64() {
+		t.Error("successive Split() calls produced the same value")
+	}
+}
+
+func TestMarshalBinaryRoundTrip(t *testing.T) {
+	r := New(3)
+	r.Float64()
+	state, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() returned error: %v", err)
+	}
+	want := r.Uint64()
+
+	restored := New(0)
+	if err := restored.UnmarshalBinary(state); err != nil {
+		t.Fatalf("UnmarshalBinary() returned error: %v", err)
+	}
+	if got := restored.Uint64(); got != want {
+		t.Errorf("restored Uint64() = %d; want %d", got, want)
+	}
+}
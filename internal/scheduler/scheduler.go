@@ -0,0 +1,532 @@
+// Package scheduler provides learning-rate schedules that drive any optimizer
+// exposing SetLearningRate.
+//
+// A schedule is advanced once per epoch (or per batch, if the caller prefers)
+// with Step, and its current value is pushed into the optimizer with Apply:
+//
+//	sched, _ := scheduler.NewCosineWarmRestarts(0.01, 1e-4, 50, 2)
+//	for epoch := 0; epoch < epochs; epoch++ {
+//		scheduler.Apply(sched, optimizer)
+//		loss := trainEpoch()
+//		sched.Step(loss)
+//	}
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Scheduler produces the learning rate for the current training step.
+type Scheduler interface {
+	// LearningRate returns the learning rate for the current step.
+	LearningRate() float64
+	// Step advances the schedule by one step and returns the new learning rate.
+	// metric is the monitored value (e.g. validation loss); schedules that do
+	// not react to it ignore it.
+	Step(metric float64) float64
+}
+
+// Optimizer is the part of an optimizer a scheduler needs to drive it.
+type Optimizer interface {
+	SetLearningRate(lr float64)
+}
+
+// State is a snapshot of a schedule's progress, used for checkpointing.
+type State map[string]float64
+
+// Stateful is implemented by schedules whose progress can be saved and restored.
+// All schedules in this package implement it.
+type Stateful interface {
+	State() State
+	LoadState(state State) error
+}
+
+// Apply sets the optimizer learning rate to the schedule's current value.
+func Apply(s Scheduler, opt Optimizer) {
+	opt.SetLearningRate(s.LearningRate())
+}
+
+//? ------------------------------
+//? Constant
+//? ------------------------------
+
+// Constant keeps the learning rate fixed. It is mostly useful as the target
+// of a LinearWarmup.
+type Constant struct {
+	LR float64
+}
+
+// NewConstant creates a constant schedule.
+func NewConstant(lr float64) (*Constant, error) {
+	if err := validateLearningRate("learning rate", lr); err != nil {
+		return nil, err
+	}
+	return &Constant{LR: lr}, nil
+}
+
+// LearningRate returns the fixed learning rate.
+func (s *Constant) LearningRate() float64 { return s.LR }
+
+// Step returns the fixed learning rate.
+func (s *Constant) Step(metric float64) float64 { return s.LR }
+
+// State returns an empty state; a constant schedule has no progress.
+func (s *Constant) State() State { return State{} }
+
+// LoadState is a no-op for a constant schedule.
+func (s *Constant) LoadState(state State) error { return nil }
+
+//? ------------------------------
+//? Step Decay
+//? ------------------------------
+
+// StepDecay multiplies the learning rate by Gamma every StepSize steps.
+//
+//	lr(t) = lr0 · γ^⌊t / stepSize⌋
+type StepDecay struct {
+	InitialLR float64
+	StepSize  int
+	Gamma     float64
+
+	step int
+}
+
+// NewStepDecay creates a step-decay schedule.
+func NewStepDecay(initialLR float64, stepSize int, gamma float64) (*StepDecay, error) {
+	if err := validateLearningRate("initial learning rate", initialLR); err != nil {
+		return nil, err
+	}
+	if stepSize <= 0 {
+		return nil, fmt.Errorf("step size must be positive, got %d", stepSize)
+	}
+	if gamma <= 0 || gamma > 1 {
+		return nil, fmt.Errorf("gamma must be in (0, 1], got %v", gamma)
+	}
+	return &StepDecay{InitialLR: initialLR, StepSize: stepSize, Gamma: gamma}, nil
+}
+
+// LearningRate returns the learning rate for the current step.
+func (s *StepDecay) LearningRate() float64 {
+	return s.InitialLR * math.Pow(s.Gamma, float64(s.step/s.StepSize))
+}
+
+// Step advances the schedule.
+func (s *StepDecay) Step(metric float64) float64 {
+	s.step++
+	return s.LearningRate()
+}
+
+// State returns the current step.
+func (s *StepDecay) State() State { return State{"step": float64(s.step)} }
+
+// LoadState restores the step saved by State.
+func (s *StepDecay) LoadState(state State) error {
+	step, err := stateStep(state)
+	if err != nil {
+		return err
+	}
+	s.step = step
+	return nil
+}
+
+//? ------------------------------
+//? Exponential Decay
+//? ------------------------------
+
+// ExponentialDecay multiplies the learning rate by Gamma every step.
+//
+//	lr(t) = lr0 · γ^t
+type ExponentialDecay struct {
+	InitialLR float64
+	Gamma     float64
+
+	step int
+}
+
+// NewExponentialDecay creates an exponential-decay schedule.
+func NewExponentialDecay(initialLR, gamma float64) (*ExponentialDecay, error) {
+	if err := validateLearningRate("initial learning rate", initialLR); err != nil {
+		return nil, err
+	}
+	if gamma <= 0 || gamma > 1 {
+		return nil, fmt.Errorf("gamma must be in (0, 1], got %v", gamma)
+	}
+	return &ExponentialDecay{InitialLR: initialLR, Gamma: gamma}, nil
+}
+
+// LearningRate returns the learning rate for the current step.
+func (s *ExponentialDecay) LearningRate() float64 {
+	return s.InitialLR * math.Pow(s.Gamma, float64(s.step))
+}
+
+// Step advances the schedule.
+func (s *ExponentialDecay) Step(metric float64) float64 {
+	s.step++
+	return s.LearningRate()
+}
+
+// State returns the current step.
+func (s *ExponentialDecay) State() State { return State{"step": float64(s.step)} }
+
+// LoadState restores the step saved by State.
+func (s *ExponentialDecay) LoadState(state State) error {
+	step, err := stateStep(state)
+	if err != nil {
+		return err
+	}
+	s.step = step
+	return nil
+}
+
+//? ------------------------------
+//? Cosine Annealing with Warm Restarts
+//? ------------------------------
+
+// CosineWarmRestarts anneals from MaxLR to MinLR along a cosine curve and
+// then restarts (SGDR). The first cycle lasts T0 steps and each following
+// cycle is TMult times longer.
+//
+//	lr(t) = min + (max - min) · (1 + cos(π · t_cur / T_i)) / 2
+type CosineWarmRestarts struct {
+	MaxLR float64
+	MinLR float64
+	T0    int
+	TMult int
+
+	step int
+}
+
+// NewCosineWarmRestarts creates a cosine-annealing schedule with warm restarts.
+// Use tMult 1 for cycles of constant length.
+func NewCosineWarmRestarts(maxLR, minLR float64, t0, tMult int) (*CosineWarmRestarts, error) {
+	if err := validateLearningRate("max learning rate", maxLR); err != nil {
+		return nil, err
+	}
+	if minLR < 0 || minLR > maxLR {
+		return nil, fmt.Errorf("min learning rate must be in [0, %v], got %v", maxLR, minLR)
+	}
+	if t0 <= 0 {
+		return nil, fmt.Errorf("T0 must be positive, got %d", t0)
+	}
+	if tMult < 1 {
+		return nil, fmt.Errorf("TMult must be at least 1, got %d", tMult)
+	}
+	return &CosineWarmRestarts{MaxLR: maxLR, MinLR: minLR, T0: t0, TMult: tMult}, nil
+}
+
+// LearningRate returns the learning rate for the current step.
+func (s *CosineWarmRestarts) LearningRate() float64 {
+	tCur, period := s.step, s.T0
+	for tCur >= period {
+		tCur -= period
+		period *= s.TMult
+	}
+	return s.MinLR + (s.MaxLR-s.MinLR)*(1+math.Cos(math.Pi*float64(tCur)/float64(period)))/2
+}
+
+// Step advances the schedule.
+func (s *CosineWarmRestarts) Step(metric float64) float64 {
+	s.step++
+	return s.LearningRate()
+}
+
+// State returns the current step.
+func (s *CosineWarmRestarts) State() State { return State{"step": float64(s.step)} }
+
+// LoadState restores the step saved by State.
+func (s *CosineWarmRestarts) LoadState(state State) error {
+	step, err := stateStep(state)
+	if err != nil {
+		return err
+	}
+	s.step = step
+	return nil
+}
+
+//? ------------------------------
+//? Linear Warmup
+//? ------------------------------
+
+// LinearWarmup ramps the learning rate linearly from StartFactor·lr to lr
+// over WarmupSteps steps, where lr is the initial value of the wrapped
+// schedule. Afterwards it defers to the wrapped schedule, which only starts
+// stepping once the warmup is over.
+type LinearWarmup struct {
+	WarmupSteps int
+	StartFactor float64
+	After       Scheduler
+
+	step int
+}
+
+// NewLinearWarmup wraps after with a linear warmup phase.
+func NewLinearWarmup(warmupSteps int, startFactor float64, after Scheduler) (*LinearWarmup, error) {
+	if warmupSteps <= 0 {
+		return nil, fmt.Errorf("warmup steps must be positive, got %d", warmupSteps)
+	}
+	if startFactor < 0 || startFactor > 1 {
+		return nil, fmt.Errorf("start factor must be in [0, 1], got %v", startFactor)
+	}
+	if after == nil {
+		return nil, errors.New("warmup requires a schedule to hand over to")
+	}
+	return &LinearWarmup{WarmupSteps: warmupSteps, StartFactor: startFactor, After: after}, nil
+}
+
+// LearningRate returns the learning rate for the current step.
+func (s *LinearWarmup) LearningRate() float64 {
+	target := s.After.LearningRate()
+	if s.step >= s.WarmupSteps {
+		return target
+	}
+	progress := float64(s.step) / float64(s.WarmupSteps)
+	return target * (s.StartFactor + (1-s.StartFactor)*progress)
+}
+
+// Step advances the warmup, or the wrapped schedule once warmup is complete.
+func (s *LinearWarmup) Step(metric float64) float64 {
+	if s.step < s.WarmupSteps {
+		s.step++
+		return s.LearningRate()
+	}
+	return s.After.Step(metric)
+}
+
+// State returns the warmup step and the wrapped schedule's state under "after.".
+func (s *LinearWarmup) State() State {
+	state := State{"step": float64(s.step)}
+	if after, ok := s.After.(Stateful); ok {
+		for k, v := range after.State() {
+			state["after."+k] = v
+		}
+	}
+	return state
+}
+
+// LoadState restores the warmup step and the wrapped schedule's state.
+func (s *LinearWarmup) LoadState(state State) error {
+	step, err := stateStep(state)
+	if err != nil {
+		return err
+	}
+	s.step = step
+
+	after, ok := s.After.(Stateful)
+	if !ok {
+		return nil
+	}
+	inner := State{}
+	for k, v := range state {
+		if strings.HasPrefix(k, "after.") {
+			inner[strings.TrimPrefix(k, "after.")] = v
+		}
+	}
+	return after.LoadState(inner)
+}
+
+//? ------------------------------
+//? One-Cycle
+//? ------------------------------
+
+// OneCycle implements the 1cycle policy: the learning rate rises from
+// MaxLR/DivFactor to MaxLR during the first PctStart of TotalSteps, then
+// anneals down to MaxLR/(DivFactor·FinalDivFactor), both along cosine curves.
+type OneCycle struct {
+	MaxLR          float64
+	TotalSteps     int
+	PctStart       float64
+	DivFactor      float64
+	FinalDivFactor float64
+
+	step int
+}
+
+// NewOneCycle creates a one-cycle schedule. Typical values are pctStart=0.3,
+// divFactor=25 and finalDivFactor=1e4.
+func NewOneCycle(maxLR float64, totalSteps int, pctStart, divFactor, finalDivFactor float64) (*OneCycle, error) {
+	if err := validateLearningRate("max learning rate", maxLR); err != nil {
+		return nil, err
+	}
+	if totalSteps <= 1 {
+		return nil, fmt.Errorf("total steps must be greater than 1, got %d", totalSteps)
+	}
+	if pctStart <= 0 || pctStart >= 1 {
+		return nil, fmt.Errorf("pctStart must be in (0, 1), got %v", pctStart)
+	}
+	if divFactor < 1 || finalDivFactor < 1 {
+		return nil, errors.New("div factors must be at least 1")
+	}
+	return &OneCycle{
+		MaxLR:          maxLR,
+		TotalSteps:     totalSteps,
+		PctStart:       pctStart,
+		DivFactor:      divFactor,
+		FinalDivFactor: finalDivFactor,
+	}, nil
+}
+
+// LearningRate returns the learning rate for the current step.
+func (s *OneCycle) LearningRate() float64 {
+	initial := s.MaxLR / s.DivFactor
+	final := initial / s.FinalDivFactor
+
+	last := s.TotalSteps - 1
+	peak := int(math.Round(s.PctStart * float64(last)))
+	switch {
+	case s.step >= last:
+		return final
+	case s.step <= peak:
+		return cosineInterp(initial, s.MaxLR, float64(s.step)/math.Max(float64(peak), 1))
+	default:
+		return cosineInterp(s.MaxLR, final, float64(s.step-peak)/float64(last-peak))
+	}
+}
+
+// Step advances the schedule.
+func (s *OneCycle) Step(metric float64) float64 {
+	s.step++
+	return s.LearningRate()
+}
+
+// State returns the current step.
+func (s *OneCycle) State() State { return State{"step": float64(s.step)} }
+
+// LoadState restores the step saved by State.
+func (s *OneCycle) LoadState(state State) error {
+	step, err := stateStep(state)
+	if err != nil {
+		return err
+	}
+	s.step = step
+	return nil
+}
+
+//? ------------------------------
+//? Reduce on Plateau
+//? ------------------------------
+
+// ReduceOnPlateau multiplies the learning rate by Factor when the monitored
+// metric (lower is better) has not improved by at least MinDelta for more than
+// Patience steps. After a reduction it waits Cooldown steps before counting
+// again, and never goes below MinLR.
+type ReduceOnPlateau struct {
+	LR       float64
+	Factor   float64
+	Patience int
+	MinDelta float64
+	Cooldown int
+	MinLR    float64
+
+	best         float64
+	badSteps     int
+	cooldownLeft int
+}
+
+// NewReduceOnPlateau creates a plateau-driven schedule starting at lr.
+func NewReduceOnPlateau(lr, factor float64, patience int, minDelta float64, cooldown int, minLR float64) (*ReduceOnPlateau, error) {
+	if err := validateLearningRate("learning rate", lr); err != nil {
+		return nil, err
+	}
+	if factor <= 0 || factor >= 1 {
+		return nil, fmt.Errorf("factor must be in (0, 1), got %v", factor)
+	}
+	if patience < 0 || cooldown < 0 {
+		return nil, errors.New("patience and cooldown must be non-negative")
+	}
+	if minDelta < 0 {
+		return nil, fmt.Errorf("min delta must be non-negative, got %v", minDelta)
+	}
+	if minLR < 0 || minLR > lr {
+		return nil, fmt.Errorf("min learning rate must be in [0, %v], got %v", lr, minLR)
+	}
+	return &ReduceOnPlateau{
+		LR:       lr,
+		Factor:   factor,
+		Patience: patience,
+		MinDelta: minDelta,
+		Cooldown: cooldown,
+		MinLR:    minLR,
+		best:     math.Inf(1),
+	}, nil
+}
+
+// LearningRate returns the current learning rate.
+func (s *ReduceOnPlateau) LearningRate() float64 { return s.LR }
+
+// Step records metric and reduces the learning rate if it has plateaued.
+func (s *ReduceOnPlateau) Step(metric float64) float64 {
+	if metric < s.best-s.MinDelta {
+		s.best = metric
+		s.badSteps = 0
+	} else {
+		s.badSteps++
+	}
+
+	if s.cooldownLeft > 0 {
+		s.cooldownLeft--
+		s.badSteps = 0
+	}
+
+	if s.badSteps > s.Patience {
+		s.LR = math.Max(s.LR*s.Factor, s.MinLR)
+		s.cooldownLeft = s.Cooldown
+		s.badSteps = 0
+	}
+	return s.LR
+}
+
+// State returns the current learning rate and plateau tracking counters.
+func (s *ReduceOnPlateau) State() State {
+	return State{
+		"lr":            s.LR,
+		"best":          s.best,
+		"bad_steps":     float64(s.badSteps),
+		"cooldown_left": float64(s.cooldownLeft),
+	}
+}
+
+// LoadState restores the values saved by State.
+func (s *ReduceOnPlateau) LoadState(state State) error {
+	for _, key := range []string{"lr", "best", "bad_steps", "cooldown_left"} {
+		if _, ok := state[key]; !ok {
+			return fmt.Errorf("plateau state is missing %q", key)
+		}
+	}
+	s.LR = state["lr"]
+	s.best = state["best"]
+	s.badSteps = int(state["bad_steps"])
+	s.cooldownLeft = int(state["cooldown_left"])
+	return nil
+}
+
+//? ------------------------------
+//? Utility Functions
+//? ------------------------------
+
+// validateLearningRate checks that a learning rate is positive and finite.
+func validateLearningRate(name string, lr float64) error {
+	if lr <= 0 || math.IsInf(lr, 0) || math.IsNaN(lr) {
+		return fmt.Errorf("%s must be positive, got %v", name, lr)
+	}
+	return nil
+}
+
+// stateStep reads a non-negative integer "step" entry.
+func stateStep(state State) (int, error) {
+	step, ok := state["step"]
+	if !ok {
+		return 0, errors.New("schedule state is missing \"step\"")
+	}
+	if step < 0 || step != math.Trunc(step) {
+		return 0, fmt.Errorf("invalid schedule step %v", step)
+	}
+	return int(step), nil
+}
+
+// cosineInterp moves from start to end along half a cosine as pct goes 0 → 1.
+func cosineInterp(start, end, pct float64) float64 {
+	return end + (start-end)*(1+math.Cos(math.Pi*pct))/2
+}
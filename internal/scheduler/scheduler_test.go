@@ -0,0 +1,174 @@
+package scheduler
+
+import (
+	"math"
+	"testing"
+)
+
+// rates steps s once per metric and returns the learning rate before the
+// first step followed by the rate after every step.
+func rates(s Scheduler, metrics []float64) []float64 {
+	lrs := []float64{s.LearningRate()}
+	for _, m := range metrics {
+		lrs = append(lrs, s.Step(m))
+	}
+	return lrs
+}
+
+// constant returns n copies of v, for schedules that ignore the metric or
+// see a metric that never improves.
+func constant(n int, v float64) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = v
+	}
+	return out
+}
+
+// halfCosine is the cosine-annealing factor (1 + cos(π·t/T)) / 2.
+func halfCosine(t, period float64) float64 {
+	return (1 + math.Cos(math.Pi*t/period)) / 2
+}
+
+func TestSchedules(t *testing.T) {
+	tests := []struct {
+		name    string
+		sched   Scheduler
+		metrics []float64
+		want    map[int]float64 // learning rate after the given number of steps
+	}{
+		{
+			name:    "step decay",
+			sched:   must(NewStepDecay(0.1, 3, 0.5)),
+			metrics: constant(6, 0),
+			want:    map[int]float64{0: 0.1, 2: 0.1, 3: 0.05, 5: 0.05, 6: 0.025},
+		},
+		{
+			name:    "exponential decay",
+			sched:   must(NewExponentialDecay(0.1, 0.9)),
+			metrics: constant(3, 0),
+			want:    map[int]float64{0: 0.1, 1: 0.09, 3: 0.0729},
+		},
+		{
+			// Cycles of 4 and 8 steps restart at steps 4 and 12.
+			name:    "cosine warm restarts",
+			sched:   must(NewCosineWarmRestarts(1, 0.2, 4, 2)),
+			metrics: constant(12, 0),
+			want: map[int]float64{
+				0:  1,
+				2:  0.6,
+				3:  0.2 + 0.8*halfCosine(3, 4),
+				4:  1,
+				8:  0.6,
+				11: 0.2 + 0.8*halfCosine(7, 8),
+				12: 1,
+			},
+		},
+		{
+			// The wrapped step decay starts stepping only after step 4.
+			name:    "linear warmup",
+			sched:   must(NewLinearWarmup(4, 0.25, must(NewStepDecay(0.1, 2, 0.5)))),
+			metrics: constant(6, 0),
+			want:    map[int]float64{0: 0.025, 2: 0.0625, 4: 0.1, 5: 0.1, 6: 0.05},
+		},
+		{
+			// Peak at round(0.3·10) = 3 steps, end at step 10 with 1/10/100.
+			name:    "one cycle",
+			sched:   must(NewOneCycle(1, 11, 0.3, 10, 100)),
+			metrics: constant(12, 0),
+			want: map[int]float64{
+				0:  0.1,
+				1:  1 - 0.9*halfCosine(1, 3),
+				3:  1,
+				10: 0.001,
+				12: 0.001,
+			},
+		},
+		{
+			// Patience 1: the second step without improvement reduces, then
+			// the cooldown of 2 steps keeps the counter at zero.
+			name:    "plateau patience and cooldown",
+			sched:   must(NewReduceOnPlateau(1, 0.5, 1, 0, 2, 0.1)),
+			metrics: constant(15, 1),
+			want:    map[int]float64{1: 1, 2: 1, 3: 0.5, 6: 0.5, 7: 0.25, 11: 0.125, 15: 0.1},
+		},
+		{
+			name:    "plateau improvement resets patience",
+			sched:   must(NewReduceOnPlateau(1, 0.5, 1, 0, 0, 0)),
+			metrics: []float64{1, 1, 0.5, 0.5, 0.5},
+			want:    map[int]float64{3: 1, 4: 1, 5: 0.5},
+		},
+	}
+
+	for _, tt := range tests {
+		lrs := rates(tt.sched, tt.metrics)
+		for step, want := range tt.want {
+			if math.Abs(lrs[step]-want) > 1e-12 {
+				t.Errorf("%s: lr after %d steps = %v; want %v", tt.name, step, lrs[step], want)
+			}
+		}
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func() Scheduler
+	}{
+		{"constant", func() Scheduler { return must(NewConstant(0.1)) }},
+		{"step decay", func() Scheduler { return must(NewStepDecay(0.1, 3, 0.5)) }},
+		{"exponential decay", func() Scheduler { return must(NewExponentialDecay(0.1, 0.9)) }},
+		{"cosine warm restarts", func() Scheduler { return must(NewCosineWarmRestarts(1, 0.2, 4, 2)) }},
+		{"linear warmup", func() Scheduler {
+			return must(NewLinearWarmup(3, 0.1, must(NewCosineWarmRestarts(1, 0, 4, 1))))
+		}},
+		{"one cycle", func() Scheduler { return must(NewOneCycle(1, 20, 0.3, 10, 100)) }},
+		{"plateau", func() Scheduler { return must(NewReduceOnPlateau(1, 0.5, 1, 0, 1, 0.01)) }},
+	}
+	metrics := []float64{5, 4, 4, 4, 3, 3, 3, 3, 2, 2, 2, 2}
+
+	for _, tt := range tests {
+		// Run the schedule straight through, and in two halves joined by a
+		// State/LoadState round trip into a fresh schedule.
+		want := rates(tt.build(), metrics)
+
+		first := tt.build()
+		got := rates(first, metrics[:5])
+		resumed := tt.build()
+		if err := resumed.(Stateful).LoadState(first.(Stateful).State()); err != nil {
+			t.Fatalf("%s: LoadState() returned error: %v", tt.name, err)
+		}
+		if lr := resumed.LearningRate(); lr != got[len(got)-1] {
+			t.Errorf("%s: restored lr = %v; want %v", tt.name, lr, got[len(got)-1])
+		}
+		got = append(got, rates(resumed, metrics[5:])[1:]...)
+
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s: lr after %d steps = %v after resuming; want %v", tt.name, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestLoadStateRejectsInvalidStep(t *testing.T) {
+	s := must(NewStepDecay(0.1, 3, 0.5))
+	for _, state := range []State{{}, {"step": -1}, {"step": 1.5}} {
+		if err := s.LoadState(state); err == nil {
+			t.Errorf("LoadState(%v) expected error, got nil", state)
+		}
+	}
+	plateau := must(NewReduceOnPlateau(1, 0.5, 1, 0, 1, 0))
+	if err := plateau.LoadState(State{"lr": 0.5}); err == nil {
+		t.Error("ReduceOnPlateau.LoadState() expected error on missing keys, got nil")
+	}
+}
+
+// must returns s, panicking on a constructor error; the tables only use
+// valid arguments.
+func must[S any](s S, err error) S {
+	if err != nil {
+		panic(err)
+	}
+	return s
+}